@@ -0,0 +1,47 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package buildinfo provides build information set with -ldflags:
+//
+//	-X github.com/networkservicemesh/cmd-nse-vfio/internal/buildinfo.Commit=<commit>
+//	-X github.com/networkservicemesh/cmd-nse-vfio/internal/buildinfo.BuildDate=<date>
+package buildinfo
+
+const (
+	// CommitLabel is a label advertising the git commit the endpoint is built from
+	CommitLabel = "commit"
+	// BuildDateLabel is a label advertising the endpoint build date
+	BuildDateLabel = "build-date"
+)
+
+var (
+	// Commit is the git commit the binary is built from
+	Commit string
+	// BuildDate is the date the binary is built at
+	BuildDate string
+)
+
+// Labels returns labels of the set build information
+func Labels() map[string]string {
+	labels := make(map[string]string, 2)
+	if Commit != "" {
+		labels[CommitLabel] = Commit
+	}
+	if BuildDate != "" {
+		labels[BuildDateLabel] = BuildDate
+	}
+	return labels
+}
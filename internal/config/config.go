@@ -1,6 +1,6 @@
 // Copyright (c) 2020-2022 Doc.ai and/or its affiliates.
 //
-// Copyright (c) 2023-2024 Cisco and/or its affiliates.
+// Copyright (c) 2023-2026 Cisco and/or its affiliates.
 //
 // Copyright (c) 2024 OpenInfra Foundation Europe. All rights reserved.
 //
@@ -22,41 +22,186 @@
 package config
 
 import (
+	"fmt"
 	"net"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/sdk/pkg/tools/cidr"
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
+)
+
+// maxVLANTag is the highest usable VLAN tag, 4095 is reserved
+const maxVLANTag = 4094
+
+const (
+	addrPrefix        = "addr:"
+	vlanPrefix        = "vlan:"
+	gatewayPrefix     = "gateway:"
+	maxConnPrefix     = "maxconn:"
+	passthroughPrefix = "passthrough:"
+	sampleRatioPrefix = "sampleratio:"
+	prefixLenPrefix   = "prefixlen:"
+	descriptionPrefix = "description:"
+	payloadPrefix     = "payload:"
+	fallbackPrefix    = "fallback:"
+	logPrefix         = "log:"
+	labelsPrefix      = "labels:"
+	vlanLabelPrefix   = "vlanlabel:"
+	vlansPrefix       = "vlans:"
+	authzPrefix       = "authz:"
+	mtuPrefix         = "mtu:"
+
+	serviceNamesEnv = "NSM_SERVICE_NAMES"
+)
+
+const (
+	// IPFamilyIPv4 - IPv4 addresses are placed first in the connection IP context
+	IPFamilyIPv4 = "ipv4"
+	// IPFamilyIPv6 - IPv6 addresses are placed first in the connection IP context
+	IPFamilyIPv6 = "ipv6"
+	// IPFamilyBoth - addresses are kept in the allocation order
+	IPFamilyBoth = "both"
+)
+
+const (
+	// ServiceChangeRemap - the connection is moved to the newly requested service
+	ServiceChangeRemap = "remap"
+	// ServiceChangeReject - requests changing the connection service are rejected
+	ServiceChangeReject = "reject"
 )
 
 const (
-	addrPrefix = "addr:"
-	vlanPrefix = "vlan:"
+	// RegistrationOrderNSFirst - network services are registered before the endpoint
+	RegistrationOrderNSFirst = "ns-first"
+	// RegistrationOrderNSEFirst - the endpoint is registered before network services
+	RegistrationOrderNSEFirst = "nse-first"
+)
+
+const (
+	// MACUniquenessDomain - MAC is unique among services of the same domain
+	MACUniquenessDomain = "domain"
+	// MACUniquenessGlobal - MAC is unique among all services
+	MACUniquenessGlobal = "global"
+)
+
+const (
+	// EmptyDomainKeep - services without domain are labeled with empty domain
+	EmptyDomainKeep = "keep"
+	// EmptyDomainOmit - services without domain are not labeled with domain
+	EmptyDomainOmit = "omit"
+	// EmptyDomainPlaceholder - services without domain are labeled with the configured placeholder
+	EmptyDomainPlaceholder = "placeholder"
 )
 
 // Config holds configuration parameters from environment variables
 type Config struct {
-	Name                   string            `default:"vfio-server" desc:"name of VFIO Server" split_words:"true"`
-	BaseDir                string            `default:"./" desc:"base directory" split_words:"true"`
-	ConnectTo              url.URL           `default:"unix:///var/lib/networkservicemesh/nsm.io.sock" desc:"url to connect to" split_words:"true"`
-	MaxTokenLifetime       time.Duration     `default:"10m" desc:"maximum lifetime of tokens" split_words:"true"`
-	RegistryClientPolicies []string          `default:"etc/nsm/opa/common/.*.rego,etc/nsm/opa/registry/.*.rego,etc/nsm/opa/client/.*.rego" desc:"paths to files and directories that contain registry client policies" split_words:"true"`
-	LogLevel               string            `default:"INFO" desc:"Log level" split_words:"true"`
-	OpenTelemetryEndpoint  string            `default:"otel-collector.observability.svc.cluster.local:4317" desc:"OpenTelemetry Collector Endpoint" split_words:"true"`
-	MetricsExportInterval  time.Duration     `default:"10s" desc:"interval between mertics exports" split_words:"true"`
-	CidrPrefix             cidr.Groups       `default:"169.254.0.0/16" desc:"List of CIDR Prefix to assign IPv4 and IPv6 addresses from" split_words:"true"`
-	Labels                 map[string]string `default:"" desc:"Endpoint labels"`
-	Payload                string            `default:"ETHERNET" desc:"Name of provided service payload" split_words:"true"`
-	PprofEnabled           bool              `default:"false" desc:"is pprof enabled" split_words:"true"`
-	PprofListenOn          string            `default:"localhost:6060" desc:"pprof URL to ListenAndServe" split_words:"true"`
-
-	ServiceNames    []ServiceConfig `default:"" desc:"list of supported services" split_words:"true"`
-	RegisterService bool            `default:"true" desc:"if true then registers network service on startup" split_words:"true"`
+	Name                        string            `default:"vfio-server" desc:"name of VFIO Server" split_words:"true"`
+	BaseDir                     string            `default:"./" desc:"base directory" split_words:"true"`
+	RandomizeSocketName         bool              `default:"false" desc:"if true then endpoint server socket name includes PID and a random suffix" split_words:"true"`
+	SocketFallbackDirs          []string          `default:"" desc:"directories tried in order to create the endpoint server socket dir in if the system temp dir fails" split_words:"true"`
+	ConnectTo                   url.URL           `default:"unix:///var/lib/networkservicemesh/nsm.io.sock" desc:"url to connect to" split_words:"true"`
+	MaxTokenLifetime            time.Duration     `default:"10m" desc:"maximum lifetime of tokens" split_words:"true"`
+	TokenLifetimeJitter         int               `default:"0" desc:"maximum percentage of max token lifetime randomly cut from every token lifetime, 0 disables jitter" split_words:"true"`
+	MinTLSVersion               TLSVersion        `default:"1.2" desc:"minimum TLS version accepted by the endpoint server: 1.2 or 1.3" split_words:"true"`
+	MinPeerValidity             time.Duration     `default:"0s" desc:"minimum remaining validity of the peer certificate accepted by the endpoint server, 0 disables the check" split_words:"true"`
+	RejectExcessiveExpiry       bool              `default:"false" desc:"if true then requests expiring later than max token lifetime are rejected instead of capped" split_words:"true"`
+	SpiffeSourceReloadAttempts  int               `default:"0" desc:"attempts to recreate the x509 source failing to provide a valid SVID before exiting, 0 disables recreation" split_words:"true"`
+	SpiffeSourceCheckInterval   time.Duration     `default:"10s" desc:"interval of checking the x509 source and of attempts to recreate it" split_words:"true"`
+	RegistryClientPolicies      []string          `default:"etc/nsm/opa/common/.*.rego,etc/nsm/opa/registry/.*.rego,etc/nsm/opa/client/.*.rego" desc:"paths to files and directories that contain registry client policies" split_words:"true"`
+	RegistryPolicyBundleURL     string            `default:"" desc:"URL of a policy bundle merged with registry client policies" split_words:"true"`
+	RegistryPolicyBundleTimeout time.Duration     `default:"10s" desc:"timeout of fetching the registry client policy bundle" split_words:"true"`
+	RegistryRetryCodes          GRPCCodes         `default:"Unavailable,DeadlineExceeded" desc:"gRPC codes of failed registry operations that are retried" split_words:"true"`
+	LogLevel                    string            `default:"INFO" desc:"Log level" split_words:"true"`
+	StackDumpSignal             string            `default:"" desc:"signal dumping goroutine stacks to the log, e.g. SIGPWR on Linux, SIGUSR1 and SIGUSR2 change the log level, empty disables it" split_words:"true"`
+	OpenTelemetryEndpoint       string            `default:"otel-collector.observability.svc.cluster.local:4317" desc:"OpenTelemetry Collector Endpoint" split_words:"true"`
+	MetricsExportInterval       time.Duration     `default:"10s" desc:"interval between mertics exports" split_words:"true"`
+	CidrPrefix                  cidr.Groups       `default:"169.254.0.0/16" desc:"List of CIDR Prefix to assign IPv4 and IPv6 addresses from" split_words:"true"`
+	FailOnCidrOverlap           bool              `default:"false" desc:"if true then overlapping CIDR prefixes fail the config" split_words:"true"`
+	Labels                      map[string]string `default:"" desc:"Endpoint labels"`
+	MaxLabels                   int               `default:"64" desc:"maximum number of endpoint labels" split_words:"true"`
+	MaxLabelValueLength         int               `default:"63" desc:"maximum length of an endpoint label value" split_words:"true"`
+	ExtraConnectionContext      map[string]string `default:"" desc:"static key/value pairs set in the extra context of every connection" split_words:"true"`
+	PayloadContextDefaults      map[string]string `default:"" desc:"paths to JSON connection contexts keyed by payload, filling the context fields not set by the request or the service" split_words:"true"`
+	StripContextFields          []string          `default:"" desc:"connection context fields cleared before the endpoint sets its own, e.g. dns_context" split_words:"true"`
+	Weight                      uint32            `default:"0" desc:"endpoint weight advertised in the weight label, 0 disables the label" split_words:"true"`
+	FallbackPayload             string            `default:"" desc:"payload of services opted into fallback and not having their own payload" split_words:"true"`
+	VFIOLabels                  bool              `default:"false" desc:"if true then advertises vfio and vfio-version labels" split_words:"true"`
+	BuildInfoLabels             bool              `default:"false" desc:"if true then advertises commit and build-date labels of the endpoint build" split_words:"true"`
+	Payload                     string            `default:"ETHERNET" desc:"Name of provided service payload" split_words:"true"`
+	ErrorBudget                 int               `default:"0" desc:"number of consecutive failed requests terminating the endpoint, 0 disables it" split_words:"true"`
+	MaxInFlightRequests         int               `default:"0" desc:"maximum number of concurrently processed requests, excess requests are rejected, 0 means unlimited" split_words:"true"`
+	RejectUntilReady            bool              `default:"false" desc:"if true then requests are rejected until the endpoint is registered" split_words:"true"`
+	MaxRecvMsgSize              int               `default:"0" desc:"maximum message size in bytes the endpoint server can receive, 0 means gRPC default" split_words:"true"`
+	MaxSendMsgSize              int               `default:"0" desc:"maximum message size in bytes the endpoint server can send, 0 means gRPC default" split_words:"true"`
+	MaxConcurrentStreams        uint32            `default:"0" desc:"maximum number of concurrent streams per client connection, 0 means gRPC default" split_words:"true"`
+	EnableCompression           bool              `default:"false" desc:"if true then the endpoint server compresses responses with gzip" split_words:"true"`
+	EnableReflection            bool              `default:"false" desc:"if true then the endpoint server serves gRPC reflection" split_words:"true"`
+	PprofEnabled                bool              `default:"false" desc:"is pprof enabled" split_words:"true"`
+	PprofListenOn               string            `default:"localhost:6060" desc:"pprof URL to ListenAndServe" split_words:"true"`
+	EventsWebhookURL            string            `default:"" desc:"URL of a webhook receiving endpoint events as JSON, empty disables it" split_words:"true"`
+	EventsWebhookTimeout        time.Duration     `default:"5s" desc:"timeout of posting an event to the webhook" split_words:"true"`
+	EventsQueueSize             int               `default:"100" desc:"maximum number of pending request-rejected events, the rest are dropped" split_words:"true"`
+	PrometheusAddress           string            `default:"" desc:"address to serve Prometheus metrics on, empty disables it" split_words:"true"`
+	HeartbeatInterval           time.Duration     `default:"0s" desc:"interval of logging uptime and active connections, 0 disables it" split_words:"true"`
+	ServiceInfoMetric           bool              `default:"false" desc:"if true then reports a gauge per supported service labeled with its name, domain and payload" split_words:"true"`
+	MappingInfoMetric           bool              `default:"false" desc:"if true then reports a gauge per supported service labeled with its name, MAC and VLAN" split_words:"true"`
+	ServiceInfoEmptyDomain      string            `default:"keep" desc:"domain label of the service info gauge for services without domain: keep, omit or placeholder" split_words:"true"`
+	ServiceInfoPlaceholder      string            `default:"default" desc:"domain label of the service info gauge for services without domain in the placeholder mode" split_words:"true"`
+	PreferredIPFamily           string            `default:"both" desc:"IP family of the primary allocated address: ipv4, ipv6 or both" split_words:"true"`
+	ServiceChangePolicy         string            `default:"remap" desc:"handling of a connection requested for another service: remap or reject" split_words:"true"`
+	RequestIDHeader             string            `default:"x-request-id" desc:"metadata header propagating request ID to the registry, empty disables it" split_words:"true"`
+	SelfTest                    bool              `default:"false" desc:"if true then requests each configured service from the started endpoint before registration" split_words:"true"`
+	SelfTestRequired            bool              `default:"false" desc:"if true then self-test failure stops the startup" split_words:"true"`
+	MaxContextSize              int               `default:"0" desc:"maximum serialized size in bytes of the requested connection context, 0 means unlimited" split_words:"true"`
+	MaxAddrsPerConnection       int               `default:"0" desc:"maximum number of client addresses allocated for a connection, 0 means unlimited" split_words:"true"`
+	MappingPlugin               string            `default:"" desc:"gRPC target of an external MAC/VLAN mapping plugin, empty uses the configured mapping only" split_words:"true"`
+	MappingPluginTimeout        time.Duration     `default:"1s" desc:"timeout of the mapping plugin call, the configured mapping is used on failure" split_words:"true"`
+	MappingPluginStrict         bool              `default:"false" desc:"if true then malformed MAC or VLAN from the mapping plugin fails the request instead of using the configured mapping" split_words:"true"`
+	MappingPluginRetries        int               `default:"0" desc:"number of retries of mapping plugin calls failed with transient errors" split_words:"true"`
+	MappingPluginRetryBackoff   time.Duration     `default:"100ms" desc:"initial backoff between mapping plugin call retries, doubled on every retry" split_words:"true"`
+	DomainMatchLabel            string            `default:"" desc:"client label carrying the domain of the request, if set then requests for services of other domains are rejected" split_words:"true"`
+	PreserveClientMAC           bool              `default:"false" desc:"if true then the configured MAC is set only if the client didn't set destination MAC" split_words:"true"`
+	ValidateContext             bool              `default:"false" desc:"if true then validates the assembled ethernet context before passing the request further" split_words:"true"`
+	DisallowUntagged            bool              `default:"false" desc:"if true then requests resolved to VLAN 0 (untagged) are rejected" split_words:"true"`
+	PathSegmentMetadata         bool              `default:"false" desc:"if true then the mapped service and time are recorded in the endpoint path segment metrics" split_words:"true"`
+	AllocationSourceContext     bool              `default:"false" desc:"if true then the connection extra context records how MAC, VLAN and client IP addresses are obtained" split_words:"true"`
+	PeerIDTelemetry             bool              `default:"false" desc:"if true then request spans and metrics are tagged with the peer SPIFFE ID" split_words:"true"`
+
+	OpenTelemetryServiceNamespace      string `default:"" desc:"service.namespace OpenTelemetry resource attribute, empty omits it" split_words:"true"`
+	OpenTelemetryServiceInstanceID     string `default:"" desc:"service.instance.id OpenTelemetry resource attribute, empty omits it" split_words:"true"`
+	OpenTelemetryDeploymentEnvironment string `default:"" desc:"deployment.environment OpenTelemetry resource attribute, empty omits it" split_words:"true"`
+
+	ServiceNames            ServiceConfigs `default:"" desc:"list of supported services" split_words:"true"`
+	SkipInvalidServices     bool           `default:"false" desc:"if true then invalid services are skipped with a warning instead of failing" split_words:"true"`
+	FailOnDuplicateMAC      bool           `default:"false" desc:"if true then services sharing MAC within the uniqueness scope fail the config instead of a warning" split_words:"true"`
+	MACUniqueness           string         `default:"domain" desc:"scope of the service MAC uniqueness check: domain or global" split_words:"true"`
+	ServiceNamePrefix       string         `default:"" desc:"prefix prepended to every supported service name" split_words:"true"`
+	DefaultDomain           string         `default:"" desc:"domain of services without domain in their names, the names are kept unchanged" split_words:"true"`
+	RegisterService         bool           `default:"true" desc:"if true then registers network service on startup" split_words:"true"`
+	RegistrationConcurrency int            `default:"1" desc:"number of network services registered in parallel" split_words:"true"`
+	MaxRegistryOperations   int            `default:"1" desc:"maximum number of concurrent registry registrations and unregistrations" split_words:"true"`
+	RegistrationOrder       string         `default:"ns-first" desc:"order of the network services and endpoint registration: ns-first or nse-first" split_words:"true"`
+	RegistrationDelay       time.Duration  `default:"0s" desc:"delay between the endpoint server start and registration" split_words:"true"`
+	MappingDumpPath         string         `default:"" desc:"path to write the resolved service mapping to as JSON, empty disables it" split_words:"true"`
+	DependencyCheck         string         `default:"" desc:"TCP host:port that should accept connections before registration, empty disables the check" split_words:"true"`
+	DependencyTimeout       time.Duration  `default:"1s" desc:"timeout of a single dependency check probe" split_words:"true"`
+	RegistrationExpiry      time.Duration  `default:"0s" desc:"expiration of the endpoint registration, 0 means max token lifetime" split_words:"true"`
+	MaxRegistrationSize     int            `default:"4194304" desc:"maximum serialized size in bytes of the endpoint registration, 0 disables the check" split_words:"true"`
+	ReconcileInterval       time.Duration  `default:"0s" desc:"interval of verifying the endpoint registration in the registry, 0 disables it" split_words:"true"`
+	ReconcileJitter         time.Duration  `default:"0s" desc:"maximum random duration added to every reconcile interval" split_words:"true"`
+	StartupTimeout          time.Duration  `default:"0s" desc:"maximum duration of the startup phases 2-5, 0 disables the limit" split_words:"true"`
+
+	warnings []string
 }
 
 // Process prints and processes env to config
@@ -67,19 +212,372 @@ func (c *Config) Process() error {
 	if err := envconfig.Process("nsm", c); err != nil {
 		return errors.Wrap(err, "cannot process envconfig nse")
 	}
+	c.appendNumberedServices()
+	if err := c.processServices(); err != nil {
+		return err
+	}
+	return c.validate()
+}
+
+// appendNumberedServices appends services of NSM_SERVICE_NAMES_0, NSM_SERVICE_NAMES_1, ... up to the first unset one
+func (c *Config) appendNumberedServices() {
+	for i := 0; ; i++ {
+		value, ok := os.LookupEnv(fmt.Sprintf("%s_%d", serviceNamesEnv, i))
+		if !ok {
+			return
+		}
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		var services ServiceConfigs
+		_ = services.Decode(value)
+		c.ServiceNames = append(c.ServiceNames, services...)
+	}
+}
+
+func (c *Config) processServices() error {
+	services := c.ServiceNames[:0]
+	for i := range c.ServiceNames {
+		service := c.ServiceNames[i]
+		if service.err != nil {
+			if !c.SkipInvalidServices {
+				return service.err
+			}
+			c.warnings = append(c.warnings, fmt.Sprintf("skipping %s", service.err.Error()))
+			continue
+		}
+		service.Name = c.ServiceNamePrefix + service.Name
+		services = append(services, service)
+	}
+	c.ServiceNames = services
+	return nil
+}
+
+// ServiceDomain resolves domain of the service: the domain of its name, then the default domain
+func (c *Config) ServiceDomain(service *ServiceConfig) string {
+	if domain := service.Domain(); domain != "" {
+		return domain
+	}
+	return c.DefaultDomain
+}
+
+// ServicePayload resolves payload of the service: service payload, then fallback payload if the service opts
+// into fallback, then the global payload
+func (c *Config) ServicePayload(service *ServiceConfig) string {
+	switch {
+	case service.Payload != "":
+		return service.Payload
+	case service.FallbackPayload && c.FallbackPayload != "":
+		return c.FallbackPayload
+	default:
+		return c.Payload
+	}
+}
+
+// ServiceLabels returns the endpoint labels merged with the service labels, service labels win on conflict
+func (c *Config) ServiceLabels(service *ServiceConfig) map[string]string {
+	labels := make(map[string]string, len(c.Labels)+len(service.Labels))
+	for key, value := range c.Labels {
+		labels[key] = value
+	}
+	for key, value := range service.Labels {
+		labels[key] = value
+	}
+	return labels
+}
+
+// Warnings returns non-fatal configuration issues found by Process
+func (c *Config) Warnings() []string {
+	return c.warnings
+}
+
+func (c *Config) validate() error {
+	switch c.PreferredIPFamily {
+	case IPFamilyIPv4, IPFamilyIPv6, IPFamilyBoth:
+	default:
+		return errors.Errorf("invalid preferred IP family: %s", c.PreferredIPFamily)
+	}
+	switch c.ServiceChangePolicy {
+	case ServiceChangeRemap, ServiceChangeReject:
+	default:
+		return errors.Errorf("invalid service change policy: %s", c.ServiceChangePolicy)
+	}
+	switch c.RegistrationOrder {
+	case RegistrationOrderNSFirst, RegistrationOrderNSEFirst:
+	default:
+		return errors.Errorf("invalid registration order: %s", c.RegistrationOrder)
+	}
+	switch c.ServiceInfoEmptyDomain {
+	case EmptyDomainKeep, EmptyDomainOmit:
+	case EmptyDomainPlaceholder:
+		if c.ServiceInfoPlaceholder == "" {
+			return errors.New("service info placeholder should not be empty")
+		}
+	default:
+		return errors.Errorf("invalid service info empty domain: %s", c.ServiceInfoEmptyDomain)
+	}
+	switch c.MACUniqueness {
+	case MACUniquenessDomain, MACUniquenessGlobal:
+	default:
+		return errors.Errorf("invalid MAC uniqueness: %s", c.MACUniqueness)
+	}
+	if c.MetricsExportInterval <= 0 {
+		return errors.Errorf("metrics export interval should be positive: %v", c.MetricsExportInterval)
+	}
+	if c.TokenLifetimeJitter < 0 || c.TokenLifetimeJitter >= 100 {
+		return errors.Errorf("token lifetime jitter should be in [0, 100) percent: %d", c.TokenLifetimeJitter)
+	}
+	if c.SpiffeSourceReloadAttempts < 0 {
+		return errors.Errorf("spiffe source reload attempts should not be negative: %d", c.SpiffeSourceReloadAttempts)
+	}
+	if c.SpiffeSourceReloadAttempts > 0 && c.SpiffeSourceCheckInterval <= 0 {
+		return errors.Errorf("spiffe source check interval should be positive: %v", c.SpiffeSourceCheckInterval)
+	}
+	if c.StackDumpSignal == "SIGUSR1" || c.StackDumpSignal == "SIGUSR2" {
+		return errors.Errorf("stack dump signal is used to change the log level: %s", c.StackDumpSignal)
+	}
+	if c.EventsQueueSize < 0 {
+		return errors.Errorf("events queue size should not be negative: %d", c.EventsQueueSize)
+	}
+	if err := c.validateLabels(); err != nil {
+		return err
+	}
+	if err := validateContextFields(c.StripContextFields); err != nil {
+		return err
+	}
+	if c.MaxRegistryOperations < 1 {
+		return errors.Errorf("max registry operations should be positive: %d", c.MaxRegistryOperations)
+	}
+	if c.RegistrationConcurrency > c.MaxRegistryOperations {
+		c.warnings = append(c.warnings, fmt.Sprintf("registration concurrency %d is limited by max registry operations %d",
+			c.RegistrationConcurrency, c.MaxRegistryOperations))
+	}
+	if opentelemetry.IsEnabled() {
+		endpoint, err := normalizeEndpoint(c.OpenTelemetryEndpoint)
+		if err != nil {
+			return err
+		}
+		c.OpenTelemetryEndpoint = endpoint
+	}
+	if c.FailOnCidrOverlap {
+		if err := checkCidrOverlaps(c.CidrPrefix); err != nil {
+			return err
+		}
+	}
+	for i := range c.ServiceNames {
+		service := &c.ServiceNames[i]
+		for _, gateway := range []net.IP{service.IPv4Gateway, service.IPv6Gateway} {
+			if gateway != nil && !c.containsIP(gateway) {
+				c.warnings = append(c.warnings, fmt.Sprintf("gateway %s of %s is not in any CIDR prefix", gateway, service.Name))
+			}
+		}
+	}
+	return c.checkDuplicateMACs()
+}
+
+// endpointContextFields are set by the endpoint chain before the stripping, IPAM allocates ip_context and the
+// mapserver sets ethernet_context
+var endpointContextFields = map[string]bool{
+	"ip_context":       true,
+	"ethernet_context": true,
+}
+
+func validateContextFields(names []string) error {
+	fields := new(networkservice.ConnectionContext).ProtoReflect().Descriptor().Fields()
+	for _, name := range names {
+		if fields.ByName(protoreflect.Name(name)) == nil {
+			return errors.Errorf("unknown connection context field: %s", name)
+		}
+		if endpointContextFields[name] {
+			return errors.Errorf("connection context field is owned by the endpoint and can't be stripped: %s", name)
+		}
+	}
+	return nil
+}
+
+// checkDuplicateMACs reports MACs shared by several services of the same domain, or of any domains in the global
+// uniqueness mode
+func (c *Config) checkDuplicateMACs() error {
+	var keys []string
+	services := make(map[string][]string)
+	for i := range c.ServiceNames {
+		service := &c.ServiceNames[i]
+		if len(service.MACAddr) == 0 {
+			continue
+		}
+		key := fmt.Sprintf("MAC %s", service.MACAddr)
+		if c.MACUniqueness != MACUniquenessGlobal {
+			key = fmt.Sprintf("%s in domain %q", key, c.ServiceDomain(service))
+		}
+		if _, ok := services[key]; !ok {
+			keys = append(keys, key)
+		}
+		services[key] = append(services[key], service.Name)
+	}
+
+	var conflicts []string
+	for _, key := range keys {
+		if len(services[key]) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("%s is shared by %s", key, strings.Join(services[key], ", ")))
+		}
+	}
+	switch {
+	case len(conflicts) == 0:
+		return nil
+	case c.FailOnDuplicateMAC:
+		return errors.Errorf("duplicate service MACs: %s", strings.Join(conflicts, "; "))
+	default:
+		c.warnings = append(c.warnings, conflicts...)
+		return nil
+	}
+}
+
+func (c *Config) validateLabels() error {
+	if err := c.checkLabels("endpoint", c.Labels); err != nil {
+		return err
+	}
+	for i := range c.ServiceNames {
+		service := &c.ServiceNames[i]
+		if err := c.checkLabels(service.Name, c.ServiceLabels(service)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) checkLabels(owner string, labels map[string]string) error {
+	if len(labels) > c.MaxLabels {
+		return errors.Errorf("too many labels of %s: %d, maximum is %d", owner, len(labels), c.MaxLabels)
+	}
+	for key, value := range labels {
+		if len(value) > c.MaxLabelValueLength {
+			return errors.Errorf("label %s value of %s is too long: %d, maximum is %d", key, owner, len(value), c.MaxLabelValueLength)
+		}
+	}
+	return nil
+}
+
+// normalizeEndpoint strips URL scheme from the endpoint and ensures it is in host:port format
+func normalizeEndpoint(endpoint string) (string, error) {
+	hostPort := strings.TrimSpace(endpoint)
+	if strings.Contains(hostPort, "://") {
+		u, err := url.Parse(hostPort)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid OpenTelemetry endpoint: %s", endpoint)
+		}
+		hostPort = u.Host
+	}
+
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid OpenTelemetry endpoint: %s", endpoint)
+	}
+	if host == "" {
+		return "", errors.Errorf("invalid OpenTelemetry endpoint: %s: host is empty", endpoint)
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", errors.Errorf("invalid OpenTelemetry endpoint: %s: invalid port", endpoint)
+	}
+	return hostPort, nil
+}
+
+// checkCidrOverlaps returns an error listing the overlapping prefixes of all the groups
+func checkCidrOverlaps(groups cidr.Groups) error {
+	var prefixes []*net.IPNet
+	for _, group := range groups {
+		prefixes = append(prefixes, group...)
+	}
+
+	var overlaps []string
+	for i, a := range prefixes {
+		for _, b := range prefixes[i+1:] {
+			if a.Contains(b.IP) || b.Contains(a.IP) {
+				overlaps = append(overlaps, fmt.Sprintf("%s and %s", a, b))
+			}
+		}
+	}
+	if len(overlaps) > 0 {
+		return errors.Errorf("overlapping CIDR prefixes: %s", strings.Join(overlaps, ", "))
+	}
+	return nil
+}
+
+func (c *Config) containsIP(ip net.IP) bool {
+	for _, group := range c.CidrPrefix {
+		for _, ipNet := range group {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ServiceConfigs is a list of per-service configs
+type ServiceConfigs []ServiceConfig
+
+// Decode expects value to be a comma-separated list of ServiceConfig. Invalid services are
+// kept with their errors to be either rejected or skipped by Config.Process.
+func (s *ServiceConfigs) Decode(value string) error {
+	*s = nil
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	for _, text := range strings.Split(value, ",") {
+		var service ServiceConfig
+		if err := service.UnmarshalBinary([]byte(text)); err != nil {
+			service = ServiceConfig{err: errors.Wrapf(err, "invalid service: %s", strings.TrimSpace(text))}
+		}
+		*s = append(*s, service)
+	}
 	return nil
 }
 
 // ServiceConfig is a per-service config
 type ServiceConfig struct {
-	Name    string
-	MACAddr net.HardwareAddr
-	VLANTag int32
+	Name        string
+	MACAddr     net.HardwareAddr
+	VLANTag     int32
+	IPv4Gateway net.IP
+	IPv6Gateway net.IP
+	// MaxConnections limits the number of simultaneous connections to the service, 0 means unlimited
+	MaxConnections int32
+	// Passthrough services are matched but their connection context is not modified
+	Passthrough bool
+	// SampleRatio overrides the trace sampling ratio of the service requests, nil means no override
+	SampleRatio *float64
+	// PrefixLength frames allocated IPv4 addresses of the service connections, 0 keeps the allocated prefix length
+	PrefixLength int32
+	// Description is sent in the network service registration metadata
+	Description string
+	// Payload overrides the global payload of the service
+	Payload string
+	// FallbackPayload services without Payload use Config.FallbackPayload instead of Config.Payload if it is set
+	FallbackPayload bool
+	// Log enables detailed logging of the service requests
+	Log bool
+	// Labels override the endpoint labels for the service
+	Labels map[string]string
+	// VLANLabel is a client label selecting the VLAN from VLANsByLabel, VLANTag is used if none matches
+	VLANLabel string
+	// VLANsByLabel maps VLANLabel values to VLAN tags
+	VLANsByLabel map[string]int32
+	// AllowedSPIFFEIDs limits the peers allowed to request the service, empty allows any peer
+	AllowedSPIFFEIDs []spiffeid.ID
+	// MTU caps the connection MTU, the lower of the client and the service MTU is used, 0 keeps the client MTU
+	MTU uint32
+
+	err error
 }
 
 // UnmarshalBinary expects string(bytes) to be in format:
-// Name: { addr: MACAddr; vlan: VLANTag; }
+// Name: { addr: MACAddr; vlan: VLANTag; gateway: Gateway; maxconn: MaxConnections; passthrough: Passthrough; sampleratio: SampleRatio; prefixlen: PrefixLength; description: Description; payload: Payload; fallback: FallbackPayload; log: Log; labels: Labels; vlanlabel: VLANLabel; vlans: VLANs; authz: SPIFFEIDs; mtu: MTU; }
 // MACAddr = xx:xx:xx:xx:xx:xx
+// Gateway = IPv4 or IPv6 address, can be set once per IP family
+// SampleRatio = float in [0, 1]
+// Labels = label_1=value_1&label_2=value_2
+// VLANs = value_1=VLANTag_1&value_2=VLANTag_2
+// SPIFFEIDs = spiffe_id_1&spiffe_id_2
 func (s *ServiceConfig) UnmarshalBinary(bytes []byte) (err error) {
 	text := string(bytes)
 
@@ -99,6 +597,34 @@ func (s *ServiceConfig) UnmarshalBinary(bytes []byte) (err error) {
 			s.MACAddr, err = net.ParseMAC(trimPrefix(part, addrPrefix))
 		case strings.HasPrefix(part, vlanPrefix):
 			s.VLANTag, err = parseInt32(trimPrefix(part, vlanPrefix))
+		case strings.HasPrefix(part, gatewayPrefix):
+			err = s.setGateway(trimPrefix(part, gatewayPrefix))
+		case strings.HasPrefix(part, maxConnPrefix):
+			s.MaxConnections, err = parseInt32(trimPrefix(part, maxConnPrefix))
+		case strings.HasPrefix(part, passthroughPrefix):
+			s.Passthrough, err = strconv.ParseBool(trimPrefix(part, passthroughPrefix))
+		case strings.HasPrefix(part, sampleRatioPrefix):
+			err = s.setSampleRatio(trimPrefix(part, sampleRatioPrefix))
+		case strings.HasPrefix(part, prefixLenPrefix):
+			s.PrefixLength, err = parseInt32(trimPrefix(part, prefixLenPrefix))
+		case strings.HasPrefix(part, descriptionPrefix):
+			s.Description = trimPrefix(part, descriptionPrefix)
+		case strings.HasPrefix(part, payloadPrefix):
+			s.Payload = trimPrefix(part, payloadPrefix)
+		case strings.HasPrefix(part, fallbackPrefix):
+			s.FallbackPayload, err = strconv.ParseBool(trimPrefix(part, fallbackPrefix))
+		case strings.HasPrefix(part, logPrefix):
+			s.Log, err = strconv.ParseBool(trimPrefix(part, logPrefix))
+		case strings.HasPrefix(part, labelsPrefix):
+			s.Labels, err = parseLabels(trimPrefix(part, labelsPrefix))
+		case strings.HasPrefix(part, vlanLabelPrefix):
+			s.VLANLabel = trimPrefix(part, vlanLabelPrefix)
+		case strings.HasPrefix(part, vlansPrefix):
+			s.VLANsByLabel, err = parseVLANs(trimPrefix(part, vlansPrefix))
+		case strings.HasPrefix(part, authzPrefix):
+			s.AllowedSPIFFEIDs, err = parseSPIFFEIDs(trimPrefix(part, authzPrefix))
+		case strings.HasPrefix(part, mtuPrefix):
+			s.MTU, err = parseUint32(trimPrefix(part, mtuPrefix))
 		default:
 			err = errors.Errorf("invalid format: %s", text)
 		}
@@ -110,6 +636,76 @@ func (s *ServiceConfig) UnmarshalBinary(bytes []byte) (err error) {
 	return s.validate()
 }
 
+func (s *ServiceConfig) setGateway(value string) error {
+	ip := net.ParseIP(value)
+	switch {
+	case ip == nil:
+		return errors.Errorf("invalid gateway: %s", value)
+	case ip.To4() != nil:
+		if s.IPv4Gateway != nil {
+			return errors.Errorf("duplicate IPv4 gateway: %s", value)
+		}
+		s.IPv4Gateway = ip
+	default:
+		if s.IPv6Gateway != nil {
+			return errors.Errorf("duplicate IPv6 gateway: %s", value)
+		}
+		s.IPv6Gateway = ip
+	}
+	return nil
+}
+
+func (s *ServiceConfig) setSampleRatio(value string) error {
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+	if ratio < 0 || ratio > 1 {
+		return errors.Errorf("sample ratio is out of range [0, 1]: %s", value)
+	}
+	s.SampleRatio = &ratio
+	return nil
+}
+
+func parseLabels(value string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(value, "&") {
+		key, labelValue, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, errors.Errorf("invalid label: %s", pair)
+		}
+		labels[key] = strings.TrimSpace(labelValue)
+	}
+	return labels, nil
+}
+
+func parseVLANs(value string) (map[string]int32, error) {
+	pairs, err := parseLabels(value)
+	if err != nil {
+		return nil, err
+	}
+	vlans := make(map[string]int32, len(pairs))
+	for labelValue, vlan := range pairs {
+		if vlans[labelValue], err = parseInt32(vlan); err != nil {
+			return nil, errors.Wrapf(err, "invalid VLAN of %s", labelValue)
+		}
+	}
+	return vlans, nil
+}
+
+func parseSPIFFEIDs(value string) ([]spiffeid.ID, error) {
+	var ids []spiffeid.ID
+	for _, text := range strings.Split(value, "&") {
+		id, err := spiffeid.FromString(strings.TrimSpace(text))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid SPIFFE ID: %s", text)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 func trimPrefix(s, prefix string) string {
 	s = strings.TrimPrefix(s, prefix)
 	return strings.TrimSpace(s)
@@ -123,9 +719,53 @@ func parseInt32(s string) (int32, error) {
 	return int32(i), nil
 }
 
+func parseUint32(s string) (uint32, error) {
+	i, err := strconv.ParseUint(s, 0, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(i), nil
+}
+
+// BaseName returns the network service name without the domain
+func (s *ServiceConfig) BaseName() string {
+	name, _, _ := strings.Cut(s.Name, "@")
+	return name
+}
+
+// Domain returns the network service domain: the part of the name after "@", empty if there is none
+func (s *ServiceConfig) Domain() string {
+	_, domain, _ := strings.Cut(s.Name, "@")
+	return domain
+}
+
 func (s *ServiceConfig) validate() error {
 	if s.Name == "" {
 		return errors.New("name is empty")
 	}
+	if s.MaxConnections < 0 {
+		return errors.Errorf("negative max connections: %d", s.MaxConnections)
+	}
+	if s.PrefixLength < 0 || s.PrefixLength > net.IPv4len*8 {
+		return errors.Errorf("IPv4 prefix length is out of range [0, 32]: %d", s.PrefixLength)
+	}
+	if len(s.VLANsByLabel) > 0 && s.VLANLabel == "" {
+		return errors.New("VLANs by label are set without VLAN label")
+	}
+	if err := checkVLANTag(s.VLANTag); err != nil {
+		return err
+	}
+	for labelValue, vlanTag := range s.VLANsByLabel {
+		if err := checkVLANTag(vlanTag); err != nil {
+			return errors.Wrapf(err, "invalid VLAN of %s", labelValue)
+		}
+	}
+	return nil
+}
+
+func checkVLANTag(vlanTag int32) error {
+	if vlanTag < 0 || vlanTag > maxVLANTag {
+		return errors.Errorf("VLAN tag is out of range [0, %d]: %d", maxVLANTag, vlanTag)
+	}
 	return nil
 }
@@ -1,6 +1,6 @@
 // Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
-// Copyright (c) 2023 Cisco and/or its affiliates.
+// Copyright (c) 2023-2026 Cisco and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -19,10 +19,16 @@
 package config_test
 
 import (
+	"crypto/tls"
+	"fmt"
 	"net"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
 
 	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
 )
@@ -54,3 +60,567 @@ func TestServiceConfig_UnmarshalBinary(t *testing.T) {
 		Name: "pingpong",
 	}, cfg)
 }
+
+func TestConfig_ServiceNamePrefix(t *testing.T) {
+	t.Setenv("NSM_SERVICE_NAMES", "pingpong: { vlan: 1111 },pongping")
+	t.Setenv("NSM_SERVICE_NAME_PREFIX", "tenant-")
+
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+
+	require.Equal(t, config.ServiceConfigs{
+		{
+			Name:    "tenant-pingpong",
+			VLANTag: 1111,
+		},
+		{
+			Name: "tenant-pongping",
+		},
+	}, cfg.ServiceNames)
+}
+
+func TestConfig_DefaultDomain(t *testing.T) {
+	t.Setenv("NSM_SERVICE_NAMES", "pingpong: { vlan: 1111 },pingpong@master.domain: { vlan: 2222 }")
+
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Empty(t, cfg.ServiceDomain(&cfg.ServiceNames[0]))
+
+	t.Setenv("NSM_DEFAULT_DOMAIN", "worker.domain")
+	t.Setenv("NSM_SERVICE_NAME_PREFIX", "vfio-")
+
+	// Names are kept, only the domain is defaulted
+	cfg = new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Equal(t, "vfio-pingpong", cfg.ServiceNames[0].Name)
+	require.Equal(t, "worker.domain", cfg.ServiceDomain(&cfg.ServiceNames[0]))
+	require.Equal(t, "vfio-pingpong@master.domain", cfg.ServiceNames[1].Name)
+	require.Equal(t, "master.domain", cfg.ServiceDomain(&cfg.ServiceNames[1]))
+}
+
+func TestServiceConfig_UnmarshalBinary_Gateway(t *testing.T) {
+	cfg := new(config.ServiceConfig)
+	err := cfg.UnmarshalBinary([]byte("pingpong: { gateway: 172.16.0.1; gateway: fd00::1 }"))
+	require.NoError(t, err)
+
+	require.Equal(t, &config.ServiceConfig{
+		Name:        "pingpong",
+		IPv4Gateway: net.ParseIP("172.16.0.1"),
+		IPv6Gateway: net.ParseIP("fd00::1"),
+	}, cfg)
+
+	cfg = new(config.ServiceConfig)
+	require.Error(t, cfg.UnmarshalBinary([]byte("pingpong: { gateway: 172.16.0.256 }")))
+
+	cfg = new(config.ServiceConfig)
+	require.Error(t, cfg.UnmarshalBinary([]byte("pingpong: { gateway: 172.16.0.1; gateway: 172.16.0.2 }")))
+}
+
+func TestConfig_GatewayWarnings(t *testing.T) {
+	t.Setenv("NSM_SERVICE_NAMES", "pingpong: { gateway: 169.254.0.1 },pongping: { gateway: 172.16.0.1 }")
+
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+
+	require.Len(t, cfg.Warnings(), 1)
+	require.Contains(t, cfg.Warnings()[0], "pongping")
+}
+
+func TestConfig_CidrOverlap(t *testing.T) {
+	t.Setenv("NSM_FAIL_ON_CIDR_OVERLAP", "true")
+	t.Setenv("NSM_CIDR_PREFIX", "[172.16.0.0/16,fd00::/64],[172.17.0.0/16,fd01::/64]")
+	require.NoError(t, new(config.Config).Process())
+
+	t.Setenv("NSM_CIDR_PREFIX", "[172.16.0.0/16,fd00::/64],[172.16.1.0/24,fd00::/96],10.0.0.0/8")
+	err := new(config.Config).Process()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "172.16.0.0/16 and 172.16.1.0/24")
+	require.Contains(t, err.Error(), "fd00::/64 and fd00::/96")
+	require.NotContains(t, err.Error(), "10.0.0.0/8")
+
+	t.Setenv("NSM_FAIL_ON_CIDR_OVERLAP", "false")
+	require.NoError(t, new(config.Config).Process())
+}
+
+func TestServiceConfig_UnmarshalBinary_MaxConnections(t *testing.T) {
+	cfg := new(config.ServiceConfig)
+	err := cfg.UnmarshalBinary([]byte("pingpong: { maxconn: 10 }"))
+	require.NoError(t, err)
+
+	require.Equal(t, &config.ServiceConfig{
+		Name:           "pingpong",
+		MaxConnections: 10,
+	}, cfg)
+
+	cfg = new(config.ServiceConfig)
+	require.Error(t, cfg.UnmarshalBinary([]byte("pingpong: { maxconn: -1 }")))
+}
+
+func TestConfig_OpenTelemetryEndpoint(t *testing.T) {
+	t.Setenv("TELEMETRY", "true")
+
+	for endpoint, expected := range map[string]string{
+		"otel-collector:4317":          "otel-collector:4317",
+		"http://otel-collector:4317":   "otel-collector:4317",
+		"https://otel-collector:4317/": "otel-collector:4317",
+		"[fd00::1]:4317":               "[fd00::1]:4317",
+	} {
+		t.Setenv("NSM_OPEN_TELEMETRY_ENDPOINT", endpoint)
+
+		cfg := new(config.Config)
+		require.NoError(t, cfg.Process(), endpoint)
+		require.Equal(t, expected, cfg.OpenTelemetryEndpoint, endpoint)
+	}
+
+	for _, endpoint := range []string{
+		"otel-collector",
+		"http://otel-collector",
+		":4317",
+		"otel-collector:port",
+		"http://%zz",
+	} {
+		t.Setenv("NSM_OPEN_TELEMETRY_ENDPOINT", endpoint)
+
+		cfg := new(config.Config)
+		require.Error(t, cfg.Process(), endpoint)
+	}
+}
+
+func TestServiceConfig_UnmarshalBinary_Passthrough(t *testing.T) {
+	cfg := new(config.ServiceConfig)
+	err := cfg.UnmarshalBinary([]byte("pingpong: { passthrough: true }"))
+	require.NoError(t, err)
+
+	require.Equal(t, &config.ServiceConfig{
+		Name:        "pingpong",
+		Passthrough: true,
+	}, cfg)
+
+	cfg = new(config.ServiceConfig)
+	require.Error(t, cfg.UnmarshalBinary([]byte("pingpong: { passthrough: maybe }")))
+}
+
+func TestConfig_EmptyServiceNames(t *testing.T) {
+	for _, value := range []string{"", " "} {
+		t.Setenv("NSM_SERVICE_NAMES", value)
+
+		cfg := new(config.Config)
+		require.NoError(t, cfg.Process())
+		require.Empty(t, cfg.ServiceNames)
+	}
+}
+
+func TestServiceConfig_Domain(t *testing.T) {
+	service := &config.ServiceConfig{Name: "pingpong@worker.domain"}
+	require.Equal(t, "pingpong", service.BaseName())
+	require.Equal(t, "worker.domain", service.Domain())
+
+	service = &config.ServiceConfig{Name: "pingpong"}
+	require.Equal(t, "pingpong", service.BaseName())
+	require.Empty(t, service.Domain())
+}
+
+func TestConfig_SkipInvalidServices(t *testing.T) {
+	t.Setenv("NSM_SERVICE_NAMES", "pingpong: { addr: 0a:55:44:33:22:11 },invalid: { addr: 0a:55 },pongping: { vlan: 1111 }")
+
+	cfg := new(config.Config)
+	require.Error(t, cfg.Process())
+
+	t.Setenv("NSM_SKIP_INVALID_SERVICES", "true")
+
+	cfg = new(config.Config)
+	require.NoError(t, cfg.Process())
+
+	require.Equal(t, config.ServiceConfigs{
+		{
+			Name:    "pingpong",
+			MACAddr: net.HardwareAddr{0x0a, 0x55, 0x44, 0x33, 0x22, 0x11},
+		},
+		{
+			Name:    "pongping",
+			VLANTag: 1111,
+		},
+	}, cfg.ServiceNames)
+	require.Len(t, cfg.Warnings(), 1)
+	require.Contains(t, cfg.Warnings()[0], "invalid")
+}
+
+func TestConfig_MinTLSVersion(t *testing.T) {
+	t.Setenv("NSM_MIN_TLS_VERSION", "1.3")
+
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Equal(t, config.TLSVersion(tls.VersionTLS13), cfg.MinTLSVersion)
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	cfg.MinTLSVersion.Apply(tlsConfig)
+	require.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+
+	t.Setenv("NSM_MIN_TLS_VERSION", "1.0")
+	require.Error(t, new(config.Config).Process())
+}
+
+func TestServiceConfig_UnmarshalBinary_SampleRatio(t *testing.T) {
+	cfg := new(config.ServiceConfig)
+	err := cfg.UnmarshalBinary([]byte("pingpong: { sampleratio: 0.25 }"))
+	require.NoError(t, err)
+
+	ratio := 0.25
+	require.Equal(t, &config.ServiceConfig{
+		Name:        "pingpong",
+		SampleRatio: &ratio,
+	}, cfg)
+
+	for _, value := range []string{"-0.1", "1.5", "half"} {
+		cfg = new(config.ServiceConfig)
+		require.Error(t, cfg.UnmarshalBinary([]byte("pingpong: { sampleratio: "+value+" }")))
+	}
+}
+
+func TestServiceConfig_UnmarshalBinary_PrefixLength(t *testing.T) {
+	cfg := new(config.ServiceConfig)
+	err := cfg.UnmarshalBinary([]byte("pingpong: { prefixlen: 30 }"))
+	require.NoError(t, err)
+
+	require.Equal(t, &config.ServiceConfig{
+		Name:         "pingpong",
+		PrefixLength: 30,
+	}, cfg)
+
+	for _, value := range []string{"-1", "33"} {
+		cfg = new(config.ServiceConfig)
+		require.Error(t, cfg.UnmarshalBinary([]byte("pingpong: { prefixlen: "+value+" }")))
+	}
+}
+
+func TestServiceConfig_UnmarshalBinary_Description(t *testing.T) {
+	cfg := new(config.ServiceConfig)
+	err := cfg.UnmarshalBinary([]byte("pingpong: { vlan: 1111; description: ping pong service }"))
+	require.NoError(t, err)
+
+	require.Equal(t, &config.ServiceConfig{
+		Name:        "pingpong",
+		VLANTag:     1111,
+		Description: "ping pong service",
+	}, cfg)
+}
+
+func TestConfig_LabelLimits(t *testing.T) {
+	t.Setenv("NSM_MAX_LABELS", "2")
+	t.Setenv("NSM_MAX_LABEL_VALUE_LENGTH", "4")
+
+	t.Setenv("NSM_LABELS", "a:1234,b:5678")
+	require.NoError(t, new(config.Config).Process())
+
+	t.Setenv("NSM_LABELS", "a:1234,b:5678,c:9")
+	require.Error(t, new(config.Config).Process())
+
+	t.Setenv("NSM_LABELS", "a:12345")
+	require.Error(t, new(config.Config).Process())
+}
+
+func TestConfig_LabelLimits_Default(t *testing.T) {
+	labels := make([]string, 64)
+	for i := range labels {
+		labels[i] = fmt.Sprintf("label-%d:%s", i, strings.Repeat("v", 63))
+	}
+
+	t.Setenv("NSM_LABELS", strings.Join(labels, ","))
+	require.NoError(t, new(config.Config).Process())
+
+	t.Setenv("NSM_LABELS", strings.Join(append(labels, "label:value"), ","))
+	require.Error(t, new(config.Config).Process())
+}
+
+func TestConfig_ServicePayload(t *testing.T) {
+	t.Setenv("NSM_PAYLOAD", "ETHERNET")
+	t.Setenv("NSM_SERVICE_NAMES", "own: { payload: IP; fallback: true },fallback: { fallback: true },global")
+
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Len(t, cfg.ServiceNames, 3)
+
+	require.Equal(t, "IP", cfg.ServicePayload(&cfg.ServiceNames[0]))
+	require.Equal(t, "ETHERNET", cfg.ServicePayload(&cfg.ServiceNames[1]))
+	require.Equal(t, "ETHERNET", cfg.ServicePayload(&cfg.ServiceNames[2]))
+
+	cfg.FallbackPayload = "IP"
+	cfg.ServiceNames[0].Payload = "ETHERNET"
+
+	require.Equal(t, "ETHERNET", cfg.ServicePayload(&cfg.ServiceNames[0]))
+	require.Equal(t, "IP", cfg.ServicePayload(&cfg.ServiceNames[1]))
+	require.Equal(t, "ETHERNET", cfg.ServicePayload(&cfg.ServiceNames[2]))
+}
+
+func TestConfig_MetricsExportInterval(t *testing.T) {
+	t.Setenv("NSM_METRICS_EXPORT_INTERVAL", "30s")
+
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Equal(t, 30*time.Second, cfg.MetricsExportInterval)
+
+	for _, interval := range []string{"0s", "-1s"} {
+		t.Setenv("NSM_METRICS_EXPORT_INTERVAL", interval)
+		require.Error(t, new(config.Config).Process())
+	}
+}
+
+func TestServiceConfig_UnmarshalBinary_Log(t *testing.T) {
+	cfg := new(config.ServiceConfig)
+	err := cfg.UnmarshalBinary([]byte("pingpong: { log: true }"))
+	require.NoError(t, err)
+
+	require.Equal(t, &config.ServiceConfig{
+		Name: "pingpong",
+		Log:  true,
+	}, cfg)
+
+	cfg = new(config.ServiceConfig)
+	require.Error(t, cfg.UnmarshalBinary([]byte("pingpong: { log: verbose }")))
+}
+
+func TestServiceConfig_UnmarshalBinary_Labels(t *testing.T) {
+	cfg := new(config.ServiceConfig)
+	err := cfg.UnmarshalBinary([]byte("pingpong: { labels: app=vfio&tier=gold }"))
+	require.NoError(t, err)
+
+	require.Equal(t, &config.ServiceConfig{
+		Name:   "pingpong",
+		Labels: map[string]string{"app": "vfio", "tier": "gold"},
+	}, cfg)
+
+	cfg = new(config.ServiceConfig)
+	require.Error(t, cfg.UnmarshalBinary([]byte("pingpong: { labels: app }")))
+}
+
+func TestConfig_ServiceLabels(t *testing.T) {
+	t.Setenv("NSM_LABELS", "app:vfio,tier:silver")
+	t.Setenv("NSM_SERVICE_NAMES", "pingpong: { labels: tier=gold&zone=a },pongping")
+
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+
+	require.Equal(t, map[string]string{
+		"app":  "vfio",
+		"tier": "gold",
+		"zone": "a",
+	}, cfg.ServiceLabels(&cfg.ServiceNames[0]))
+	require.Equal(t, map[string]string{
+		"app":  "vfio",
+		"tier": "silver",
+	}, cfg.ServiceLabels(&cfg.ServiceNames[1]))
+
+	t.Setenv("NSM_MAX_LABELS", "2")
+	require.Error(t, new(config.Config).Process())
+}
+
+func TestServiceConfig_UnmarshalBinary_VLANsByLabel(t *testing.T) {
+	cfg := new(config.ServiceConfig)
+	err := cfg.UnmarshalBinary([]byte("pingpong: { vlan: 1; vlanlabel: tenant; vlans: red=100&blue=200 }"))
+	require.NoError(t, err)
+
+	require.Equal(t, &config.ServiceConfig{
+		Name:      "pingpong",
+		VLANTag:   1,
+		VLANLabel: "tenant",
+		VLANsByLabel: map[string]int32{
+			"red":  100,
+			"blue": 200,
+		},
+	}, cfg)
+
+	cfg = new(config.ServiceConfig)
+	require.Error(t, cfg.UnmarshalBinary([]byte("pingpong: { vlanlabel: tenant; vlans: red=vlan }")))
+
+	cfg = new(config.ServiceConfig)
+	require.Error(t, cfg.UnmarshalBinary([]byte("pingpong: { vlans: red=100 }")))
+
+	for _, text := range []string{
+		"pingpong: { vlan: -1 }",
+		"pingpong: { vlan: 4095 }",
+		"pingpong: { vlanlabel: tenant; vlans: red=100&blue=5000 }",
+		"pingpong: { vlanlabel: tenant; vlans: red=-1 }",
+	} {
+		cfg = new(config.ServiceConfig)
+		err = cfg.UnmarshalBinary([]byte(text))
+		require.Error(t, err, text)
+		require.Contains(t, err.Error(), "out of range [0, 4094]", text)
+	}
+
+	cfg = new(config.ServiceConfig)
+	require.NoError(t, cfg.UnmarshalBinary([]byte("pingpong: { vlan: 4094; vlanlabel: tenant; vlans: red=0 }")))
+}
+
+func TestConfig_NumberedServiceNames(t *testing.T) {
+	t.Setenv("NSM_SERVICE_NAMES_0", "pingpong: { vlan: 1 }")
+
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Len(t, cfg.ServiceNames, 1)
+	require.Equal(t, "pingpong", cfg.ServiceNames[0].Name)
+
+	t.Setenv("NSM_SERVICE_NAMES", "pongping")
+	t.Setenv("NSM_SERVICE_NAMES_1", "service-1a,service-1b")
+	t.Setenv("NSM_SERVICE_NAMES_2", "service-2")
+	t.Setenv("NSM_SERVICE_NAMES_4", "service-4")
+
+	cfg = new(config.Config)
+	require.NoError(t, cfg.Process())
+
+	var names []string
+	for i := range cfg.ServiceNames {
+		names = append(names, cfg.ServiceNames[i].Name)
+	}
+	require.Equal(t, []string{"pongping", "pingpong", "service-1a", "service-1b", "service-2"}, names)
+}
+
+func TestConfig_ServiceChangePolicy(t *testing.T) {
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Equal(t, config.ServiceChangeRemap, cfg.ServiceChangePolicy)
+
+	t.Setenv("NSM_SERVICE_CHANGE_POLICY", "reject")
+	cfg = new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Equal(t, config.ServiceChangeReject, cfg.ServiceChangePolicy)
+
+	t.Setenv("NSM_SERVICE_CHANGE_POLICY", "ignore")
+	require.Error(t, new(config.Config).Process())
+}
+
+func TestConfig_DuplicateMACs(t *testing.T) {
+	t.Setenv("NSM_SERVICE_NAMES", "pingpong: { addr: 0a:55:44:33:22:11 },"+
+		"pongping: { addr: 0a:55:44:33:22:11 },"+
+		"pingpong@worker: { addr: 0a:55:44:33:22:11 },"+
+		"other: { addr: 0a:55:44:33:22:22 }")
+
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Equal(t, []string{
+		`MAC 0a:55:44:33:22:11 in domain "" is shared by pingpong, pongping`,
+	}, cfg.Warnings())
+
+	t.Setenv("NSM_FAIL_ON_DUPLICATE_MAC", "true")
+	err := new(config.Config).Process()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pingpong, pongping")
+}
+
+func TestConfig_DistinctMACs(t *testing.T) {
+	t.Setenv("NSM_FAIL_ON_DUPLICATE_MAC", "true")
+	t.Setenv("NSM_SERVICE_NAMES", "pingpong: { addr: 0a:55:44:33:22:11 },"+
+		"pingpong@worker: { addr: 0a:55:44:33:22:11 },"+
+		"pongping: { addr: 0a:55:44:33:22:22 },"+
+		"nomac-1,nomac-2")
+
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Empty(t, cfg.Warnings())
+}
+
+func TestConfig_MACUniqueness(t *testing.T) {
+	t.Setenv("NSM_FAIL_ON_DUPLICATE_MAC", "true")
+	t.Setenv("NSM_SERVICE_NAMES", "pingpong@master: { addr: 0a:55:44:33:22:11 },"+
+		"pingpong@worker: { addr: 0a:55:44:33:22:11 }")
+
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Equal(t, config.MACUniquenessDomain, cfg.MACUniqueness)
+
+	t.Setenv("NSM_MAC_UNIQUENESS", "global")
+	err := new(config.Config).Process()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "MAC 0a:55:44:33:22:11 is shared by pingpong@master, pingpong@worker")
+
+	t.Setenv("NSM_MAC_UNIQUENESS", "cluster")
+	require.Error(t, new(config.Config).Process())
+}
+
+func TestConfig_ServiceInfoEmptyDomain(t *testing.T) {
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Equal(t, config.EmptyDomainKeep, cfg.ServiceInfoEmptyDomain)
+
+	t.Setenv("NSM_SERVICE_INFO_EMPTY_DOMAIN", "placeholder")
+	t.Setenv("NSM_SERVICE_INFO_PLACEHOLDER", "")
+	require.Error(t, new(config.Config).Process())
+
+	t.Setenv("NSM_SERVICE_INFO_EMPTY_DOMAIN", "drop")
+	require.Error(t, new(config.Config).Process())
+}
+
+func TestConfig_StackDumpSignal(t *testing.T) {
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Empty(t, cfg.StackDumpSignal)
+
+	t.Setenv("NSM_STACK_DUMP_SIGNAL", "SIGPWR")
+	cfg = new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Equal(t, "SIGPWR", cfg.StackDumpSignal)
+
+	t.Setenv("NSM_STACK_DUMP_SIGNAL", "SIGUSR1")
+	err := new(config.Config).Process()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "log level")
+}
+
+func TestConfig_RegistryRetryCodes(t *testing.T) {
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Equal(t, config.GRPCCodes{codes.Unavailable, codes.DeadlineExceeded}, cfg.RegistryRetryCodes)
+
+	t.Setenv("NSM_REGISTRY_RETRY_CODES", "Unavailable, ResourceExhausted")
+	cfg = new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Equal(t, config.GRPCCodes{codes.Unavailable, codes.ResourceExhausted}, cfg.RegistryRetryCodes)
+
+	t.Setenv("NSM_REGISTRY_RETRY_CODES", "Unavailable,Retry")
+	require.Error(t, new(config.Config).Process())
+}
+
+func TestConfig_StripContextFields(t *testing.T) {
+	t.Setenv("NSM_STRIP_CONTEXT_FIELDS", "dns_context,extra_context")
+
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+	require.Equal(t, []string{"dns_context", "extra_context"}, cfg.StripContextFields)
+
+	t.Setenv("NSM_STRIP_CONTEXT_FIELDS", "dns_context,vlan_context")
+	err := new(config.Config).Process()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "vlan_context")
+
+	for _, field := range []string{"ip_context", "ethernet_context"} {
+		t.Setenv("NSM_STRIP_CONTEXT_FIELDS", "dns_context,"+field)
+		err = new(config.Config).Process()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "owned by the endpoint")
+	}
+}
+
+func TestServiceConfig_UnmarshalBinary_AllowedSPIFFEIDs(t *testing.T) {
+	cfg := new(config.ServiceConfig)
+	err := cfg.UnmarshalBinary([]byte("pingpong: { authz: spiffe://example.org/forwarder&spiffe://example.org/nsmgr }"))
+	require.NoError(t, err)
+
+	require.Equal(t, &config.ServiceConfig{
+		Name: "pingpong",
+		AllowedSPIFFEIDs: []spiffeid.ID{
+			spiffeid.RequireFromString("spiffe://example.org/forwarder"),
+			spiffeid.RequireFromString("spiffe://example.org/nsmgr"),
+		},
+	}, cfg)
+
+	cfg = new(config.ServiceConfig)
+	require.Error(t, cfg.UnmarshalBinary([]byte("pingpong: { authz: example.org/forwarder }")))
+}
+
+func TestServiceConfig_UnmarshalBinary_MTU(t *testing.T) {
+	cfg := new(config.ServiceConfig)
+	require.NoError(t, cfg.UnmarshalBinary([]byte("pingpong: { mtu: 1500 }")))
+	require.Equal(t, uint32(1500), cfg.MTU)
+
+	cfg = new(config.ServiceConfig)
+	require.Error(t, cfg.UnmarshalBinary([]byte("pingpong: { mtu: -1 }")))
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// GRPCCodes is a list of gRPC codes decoded from comma-separated names like "Unavailable,DeadlineExceeded"
+type GRPCCodes []codes.Code
+
+// Decode parses gRPC codes from value
+func (c *GRPCCodes) Decode(value string) error {
+	*c = nil
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		code, ok := parseCode(name)
+		if !ok {
+			return errors.Errorf("unknown gRPC code: %s", name)
+		}
+		*c = append(*c, code)
+	}
+	return nil
+}
+
+func parseCode(name string) (codes.Code, bool) {
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		if code.String() == name {
+			return code, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/tls"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TLSVersion is a TLS protocol version decoded from "1.2" or "1.3"
+type TLSVersion uint16
+
+// Decode parses TLS version from value
+func (v *TLSVersion) Decode(value string) error {
+	switch strings.TrimSpace(value) {
+	case "1.2":
+		*v = tls.VersionTLS12
+	case "1.3":
+		*v = tls.VersionTLS13
+	default:
+		return errors.Errorf("unsupported TLS version: %s", value)
+	}
+	return nil
+}
+
+// Apply sets v as the minimum TLS version of tlsConfig
+func (v TLSVersion) Apply(tlsConfig *tls.Config) {
+	tlsConfig.MinVersion = uint16(v)
+}
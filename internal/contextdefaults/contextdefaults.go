@@ -0,0 +1,45 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package contextdefaults provides loading of per-payload connection context defaults
+package contextdefaults
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// Load reads connection contexts in the protobuf JSON format from the files at paths keyed by payload
+func Load(paths map[string]string) (map[string]*networkservice.ConnectionContext, error) {
+	defaults := make(map[string]*networkservice.ConnectionContext, len(paths))
+	for payload, path := range paths {
+		// #nosec G304 - path is set by the operator
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s context defaults", payload)
+		}
+		connContext := new(networkservice.ConnectionContext)
+		if err := protojson.Unmarshal(data, connContext); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s context defaults from %s", payload, path)
+		}
+		defaults[payload] = connContext
+	}
+	return defaults, nil
+}
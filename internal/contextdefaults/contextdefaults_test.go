@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contextdefaults_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/contextdefaults"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	ethernetPath := filepath.Join(dir, "ethernet.json")
+	require.NoError(t, os.WriteFile(ethernetPath, []byte(`{"MTU": 9000, "extraContext": {"tier": "gold"}}`), 0o600))
+	ipPath := filepath.Join(dir, "ip.json")
+	require.NoError(t, os.WriteFile(ipPath, []byte(`{"MTU": 1500}`), 0o600))
+
+	defaults, err := contextdefaults.Load(map[string]string{"ETHERNET": ethernetPath, "IP": ipPath})
+	require.NoError(t, err)
+	require.Len(t, defaults, 2)
+	require.Equal(t, uint32(9000), defaults["ETHERNET"].GetMTU())
+	require.Equal(t, map[string]string{"tier": "gold"}, defaults["ETHERNET"].GetExtraContext())
+	require.Equal(t, uint32(1500), defaults["IP"].GetMTU())
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ethernet.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"mtu": "jumbo"}`), 0o600))
+
+	_, err := contextdefaults.Load(map[string]string{"ETHERNET": path})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ETHERNET")
+
+	_, err = contextdefaults.Load(map[string]string{"IP": filepath.Join(dir, "missing.json")})
+	require.Error(t, err)
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events provides emitters of the endpoint lifecycle events
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const (
+	// Registered is emitted when the endpoint is registered
+	Registered = "registered"
+	// RequestRejected is emitted when a connection request fails
+	RequestRejected = "request-rejected"
+)
+
+// Event is an endpoint lifecycle event
+type Event struct {
+	Type       string            `json:"type"`
+	Endpoint   string            `json:"endpoint"`
+	Time       time.Time         `json:"time"`
+	Message    string            `json:"message,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Emitter emits events to a sink, failures are logged and don't affect the caller
+type Emitter interface {
+	Emit(ctx context.Context, event *Event)
+}
+
+type discard struct{}
+
+func (discard) Emit(context.Context, *Event) {}
+
+// Discard is an emitter dropping all events
+var Discard Emitter = discard{}
+
+type webhookEmitter struct {
+	endpoint string
+	url      string
+	client   *http.Client
+}
+
+// NewWebhookEmitter returns an emitter posting events of the endpoint as JSON to the webhook url
+func NewWebhookEmitter(endpoint, url string, timeout time.Duration) Emitter {
+	return &webhookEmitter{
+		endpoint: endpoint,
+		url:      url,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (e *webhookEmitter) Emit(ctx context.Context, event *Event) {
+	if err := e.post(ctx, event); err != nil {
+		log.FromContext(ctx).Warnf("failed to emit %s event: %s", event.Type, err.Error())
+	}
+}
+
+func (e *webhookEmitter) post(ctx context.Context, event *Event) error {
+	event.Endpoint = e.endpoint
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal event")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to create request to %s", e.url)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to post event to %s", e.url)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("webhook %s responded with %s", e.url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/log/logruslogger"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/events"
+)
+
+func TestWebhookEmitter(t *testing.T) {
+	received := make(chan *events.Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		event := new(events.Event)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(event))
+		received <- event
+	}))
+	defer server.Close()
+
+	emitter := events.NewWebhookEmitter("vfio-server", server.URL, time.Second)
+	emitter.Emit(context.Background(), &events.Event{
+		Type:       events.Registered,
+		Attributes: map[string]string{"url": "unix:///listen.on"},
+	})
+
+	event := <-received
+	require.Equal(t, events.Registered, event.Type)
+	require.Equal(t, "vfio-server", event.Endpoint)
+	require.Equal(t, map[string]string{"url": "unix:///listen.on"}, event.Attributes)
+	require.WithinDuration(t, time.Now(), event.Time, time.Minute)
+}
+
+func TestWebhookEmitter_Failure(t *testing.T) {
+	hook := test.NewGlobal()
+	ctx := log.WithLog(context.Background(), logruslogger.New(context.Background()))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	events.NewWebhookEmitter("vfio-server", server.URL, time.Second).Emit(ctx, &events.Event{Type: events.Registered})
+
+	require.Contains(t, hook.LastEntry().Message, "failed to emit registered event")
+}
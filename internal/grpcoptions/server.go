@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpcoptions provides gRPC options and optional services built from the config
+package grpcoptions
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+)
+
+// ServerOptions returns gRPC server tuning options, unset values keep gRPC defaults
+func ServerOptions(cfg *config.Config) []grpc.ServerOption {
+	var options []grpc.ServerOption
+	if cfg.MaxRecvMsgSize > 0 {
+		options = append(options, grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize))
+	}
+	if cfg.MaxSendMsgSize > 0 {
+		options = append(options, grpc.MaxSendMsgSize(cfg.MaxSendMsgSize))
+	}
+	if cfg.MaxConcurrentStreams > 0 {
+		options = append(options, grpc.MaxConcurrentStreams(cfg.MaxConcurrentStreams))
+	}
+	if cfg.EnableCompression {
+		options = append(options,
+			grpc.ChainUnaryInterceptor(compressUnary),
+			grpc.ChainStreamInterceptor(compressStream),
+		)
+	}
+	return options
+}
+
+// compressUnary compresses responses with gzip if the client accepts it, gzip requests are always accepted
+func compressUnary(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	_ = grpc.SetSendCompressor(ctx, gzip.Name)
+	return handler(ctx, req)
+}
+
+func compressStream(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	_ = grpc.SetSendCompressor(ss.Context(), gzip.Name)
+	return handler(srv, ss)
+}
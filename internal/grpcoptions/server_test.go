@@ -0,0 +1,182 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcoptions_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/grpcoptions"
+)
+
+func TestServerOptions(t *testing.T) {
+	require.Empty(t, grpcoptions.ServerOptions(new(config.Config)))
+	require.Len(t, grpcoptions.ServerOptions(&config.Config{
+		MaxRecvMsgSize:       1024,
+		MaxSendMsgSize:       1024,
+		MaxConcurrentStreams: 10,
+		EnableCompression:    true,
+	}), 5)
+}
+
+func TestServerOptions_MaxRecvMsgSize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := grpc.NewServer(grpcoptions.ServerOptions(&config.Config{MaxRecvMsgSize: 64})...)
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+
+	listener := bufconn.Listen(1024 * 1024)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	cc, err := grpc.DialContext(ctx, "bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer func() { _ = cc.Close() }()
+
+	healthClient := grpc_health_v1.NewHealthClient(cc)
+
+	_, err = healthClient.Check(ctx, new(grpc_health_v1.HealthCheckRequest))
+	require.NoError(t, err)
+
+	_, err = healthClient.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: strings.Repeat("a", 128)})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+type encodingHandler struct {
+	encoding chan string
+}
+
+func (h *encodingHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h *encodingHandler) HandleRPC(_ context.Context, s stats.RPCStats) {
+	if in, ok := s.(*stats.InHeader); ok {
+		h.encoding <- in.Compression
+	}
+}
+
+func (h *encodingHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *encodingHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+func TestServerOptions_EnableCompression(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		enabled := enabled
+		t.Run(strconv.FormatBool(enabled), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			server := grpc.NewServer(grpcoptions.ServerOptions(&config.Config{EnableCompression: enabled})...)
+			grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+
+			listener := bufconn.Listen(1024 * 1024)
+			go func() { _ = server.Serve(listener) }()
+			defer server.Stop()
+
+			handler := &encodingHandler{encoding: make(chan string, 2)}
+			cc, err := grpc.DialContext(ctx, "bufconn",
+				grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+				grpc.WithStatsHandler(handler),
+			)
+			require.NoError(t, err)
+			defer func() { _ = cc.Close() }()
+
+			healthClient := grpc_health_v1.NewHealthClient(cc)
+
+			_, err = healthClient.Check(ctx, new(grpc_health_v1.HealthCheckRequest))
+			require.NoError(t, err)
+			if enabled {
+				require.Equal(t, gzip.Name, <-handler.encoding)
+			} else {
+				require.Empty(t, <-handler.encoding)
+			}
+
+			resp, err := healthClient.Check(ctx, new(grpc_health_v1.HealthCheckRequest), grpc.UseCompressor(gzip.Name))
+			require.NoError(t, err)
+			require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.GetStatus())
+			require.Equal(t, gzip.Name, <-handler.encoding)
+		})
+	}
+}
+
+func TestRegisterReflection(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		enabled := enabled
+		t.Run(strconv.FormatBool(enabled), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			server := grpc.NewServer()
+			grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+			grpcoptions.RegisterReflection(server, &config.Config{EnableReflection: enabled})
+
+			listener := bufconn.Listen(1024 * 1024)
+			go func() { _ = server.Serve(listener) }()
+			defer server.Stop()
+
+			cc, err := grpc.DialContext(ctx, "bufconn",
+				grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			)
+			require.NoError(t, err)
+			defer func() { _ = cc.Close() }()
+
+			stream, err := grpc_reflection_v1.NewServerReflectionClient(cc).ServerReflectionInfo(ctx)
+			require.NoError(t, err)
+			require.NoError(t, stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+				MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+			}))
+
+			resp, err := stream.Recv()
+			if !enabled {
+				require.Equal(t, codes.Unimplemented, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+
+			var services []string
+			for _, service := range resp.GetListServicesResponse().GetService() {
+				services = append(services, service.GetName())
+			}
+			require.Contains(t, services, grpc_health_v1.Health_ServiceDesc.ServiceName)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mappingdump provides a writer of the resolved `network service -> { MAC, VLAN }` mapping
+package mappingdump
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+)
+
+// Entry is a resolved mapping of a single network service. Mappings resolved by the mapping plugin at request time
+// are not known in advance, so the configured mapping is dumped for them.
+type Entry struct {
+	Service          string            `json:"service"`
+	Domain           string            `json:"domain,omitempty"`
+	Payload          string            `json:"payload"`
+	MACAddr          string            `json:"macAddr,omitempty"`
+	VLANTag          int32             `json:"vlanTag"`
+	VLANLabel        string            `json:"vlanLabel,omitempty"`
+	VLANsByLabel     map[string]int32  `json:"vlansByLabel,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	IPv4Gateway      string            `json:"ipv4Gateway,omitempty"`
+	IPv6Gateway      string            `json:"ipv6Gateway,omitempty"`
+	PrefixLength     int32             `json:"prefixLength,omitempty"`
+	MTU              uint32            `json:"mtu,omitempty"`
+	AllowedSPIFFEIDs []string          `json:"allowedSpiffeIds,omitempty"`
+	MaxConnections   int32             `json:"maxConnections"`
+	Passthrough      bool              `json:"passthrough"`
+}
+
+// Write writes the mapping of the configured services to the file at path as JSON
+func Write(path string, cfg *config.Config) error {
+	entries := make([]Entry, len(cfg.ServiceNames))
+	for i := range cfg.ServiceNames {
+		service := &cfg.ServiceNames[i]
+		entries[i] = Entry{
+			Service:        service.Name,
+			Domain:         cfg.ServiceDomain(service),
+			Payload:        cfg.ServicePayload(service),
+			MACAddr:        service.MACAddr.String(),
+			VLANTag:        service.VLANTag,
+			VLANLabel:      service.VLANLabel,
+			VLANsByLabel:   service.VLANsByLabel,
+			PrefixLength:   service.PrefixLength,
+			MTU:            service.MTU,
+			MaxConnections: service.MaxConnections,
+			Passthrough:    service.Passthrough,
+		}
+		if labels := cfg.ServiceLabels(service); len(labels) > 0 {
+			entries[i].Labels = labels
+		}
+		if service.IPv4Gateway != nil {
+			entries[i].IPv4Gateway = service.IPv4Gateway.String()
+		}
+		if service.IPv6Gateway != nil {
+			entries[i].IPv6Gateway = service.IPv6Gateway.String()
+		}
+		for _, id := range service.AllowedSPIFFEIDs {
+			entries[i].AllowedSPIFFEIDs = append(entries[i].AllowedSPIFFEIDs, id.String())
+		}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal service mapping")
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return errors.Wrapf(err, "failed to write service mapping to %s", path)
+	}
+	return nil
+}
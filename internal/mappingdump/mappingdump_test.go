@@ -0,0 +1,90 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mappingdump_test
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/mappingdump"
+)
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapping.json")
+
+	err := mappingdump.Write(path, &config.Config{
+		Payload: "ETHERNET",
+		Labels:  map[string]string{"app": "vfio"},
+		ServiceNames: []config.ServiceConfig{
+			{
+				Name:             "pingpong@worker.domain",
+				MACAddr:          net.HardwareAddr{0x0a, 0x55, 0x44, 0x33, 0x22, 0x11},
+				VLANTag:          1111,
+				VLANLabel:        "tenant",
+				VLANsByLabel:     map[string]int32{"red": 100},
+				IPv4Gateway:      net.ParseIP("169.254.0.1"),
+				PrefixLength:     24,
+				MTU:              1400,
+				Payload:          "IP",
+				AllowedSPIFFEIDs: []spiffeid.ID{spiffeid.RequireFromString("spiffe://example.org/client")},
+			},
+			{
+				Name:           "pongping",
+				MaxConnections: 2,
+				Passthrough:    true,
+				Labels:         map[string]string{"app": "pongping"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	require.NoError(t, err)
+
+	var entries []mappingdump.Entry
+	require.NoError(t, json.Unmarshal(data, &entries))
+	require.Equal(t, []mappingdump.Entry{
+		{
+			Service:          "pingpong@worker.domain",
+			Domain:           "worker.domain",
+			Payload:          "IP",
+			MACAddr:          "0a:55:44:33:22:11",
+			VLANTag:          1111,
+			VLANLabel:        "tenant",
+			VLANsByLabel:     map[string]int32{"red": 100},
+			Labels:           map[string]string{"app": "vfio"},
+			IPv4Gateway:      "169.254.0.1",
+			PrefixLength:     24,
+			MTU:              1400,
+			AllowedSPIFFEIDs: []string{"spiffe://example.org/client"},
+		},
+		{
+			Service:        "pongping",
+			Payload:        "ETHERNET",
+			Labels:         map[string]string{"app": "pongping"},
+			MaxConnections: 2,
+			Passthrough:    true,
+		},
+	}, entries)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mappingplugin provides a gRPC client and server of an external `network service -> { MAC, VLAN }` resolver.
+//
+// The plugin serves the unary method /mappingplugin.MappingPlugin/Resolve. Both request and response are
+// google.protobuf.Struct messages:
+//
+//	request:  { "service": string, "connectionId": string }
+//	response: { "mac": string, "vlan": number }
+package mappingplugin
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	serviceName    = "mappingplugin.MappingPlugin"
+	resolveMethod  = "Resolve"
+	resolveFullURI = "/" + serviceName + "/" + resolveMethod
+
+	serviceKey = "service"
+	connIDKey  = "connectionId"
+	macKey     = "mac"
+	vlanKey    = "vlan"
+)
+
+// ErrMalformedMapping is wrapped by the errors of the resolved mapping that can't be applied
+var ErrMalformedMapping = errors.New("malformed mapping")
+
+// Resolver resolves MAC and VLAN of the network service connection
+type Resolver interface {
+	Resolve(ctx context.Context, service, connID string) (net.HardwareAddr, int32, error)
+}
+
+// TransportCredentials returns credentials of the plugin target. Unix socket targets are local and protected by the
+// file permissions, so they are plaintext, other targets use mTLS with tlsConfig.
+func TransportCredentials(target string, tlsConfig *tls.Config) credentials.TransportCredentials {
+	if strings.HasPrefix(target, "unix:") {
+		return insecure.NewCredentials()
+	}
+	return credentials.NewTLS(tlsConfig)
+}
+
+type client struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewClient returns a Resolver calling the plugin over cc
+func NewClient(cc grpc.ClientConnInterface) Resolver {
+	return &client{cc: cc}
+}
+
+func (c *client) Resolve(ctx context.Context, service, connID string) (net.HardwareAddr, int32, error) {
+	req := &structpb.Struct{Fields: map[string]*structpb.Value{
+		serviceKey: structpb.NewStringValue(service),
+		connIDKey:  structpb.NewStringValue(connID),
+	}}
+	resp := new(structpb.Struct)
+	if err := c.cc.Invoke(ctx, resolveFullURI, req, resp); err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to resolve %s", service)
+	}
+
+	mac, err := net.ParseMAC(resp.GetFields()[macKey].GetStringValue())
+	if err != nil {
+		return nil, 0, errors.Wrapf(ErrMalformedMapping, "plugin returned invalid MAC for %s: %s", service, err.Error())
+	}
+	vlan := resp.GetFields()[vlanKey].GetNumberValue()
+	if vlan < 0 || vlan > 4094 || vlan != float64(int32(vlan)) {
+		return nil, 0, errors.Wrapf(ErrMalformedMapping, "plugin returned invalid VLAN for %s: %v", service, vlan)
+	}
+	return mac, int32(vlan), nil
+}
+
+// RegisterServer registers resolver as the plugin service on s
+func RegisterServer(s *grpc.Server, resolver Resolver) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: serviceName,
+		HandlerType: (*Resolver)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: resolveMethod,
+			Handler:    resolveHandler,
+		}},
+	}, resolver)
+}
+
+func resolveHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := new(structpb.Struct)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		fields := req.(*structpb.Struct).GetFields()
+		mac, vlan, err := srv.(Resolver).Resolve(ctx, fields[serviceKey].GetStringValue(), fields[connIDKey].GetStringValue())
+		if err != nil {
+			return nil, err
+		}
+		return &structpb.Struct{Fields: map[string]*structpb.Value{
+			macKey:  structpb.NewStringValue(mac.String()),
+			vlanKey: structpb.NewNumberValue(float64(vlan)),
+		}}, nil
+	}
+	if interceptor == nil {
+		return handler(ctx, req)
+	}
+	return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: resolveFullURI}, handler)
+}
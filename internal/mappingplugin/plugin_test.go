@@ -0,0 +1,116 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mappingplugin_test
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/mappingplugin"
+)
+
+type fakeResolver struct{}
+
+func (fakeResolver) Resolve(_ context.Context, service, connID string) (net.HardwareAddr, int32, error) {
+	if service != "pingpong" {
+		return nil, 0, status.Errorf(codes.NotFound, "unknown service: %s", service)
+	}
+	if connID == "id-malformed" {
+		return nil, 1111, nil
+	}
+	if connID == "id-vlan-4095" {
+		return net.HardwareAddr{0x0a, 0x00, 0x00, 0x00, 0x00, 0x01}, 4095, nil
+	}
+	if connID == "id-2" {
+		return net.HardwareAddr{0x0a, 0x00, 0x00, 0x00, 0x00, 0x02}, 2222, nil
+	}
+	return net.HardwareAddr{0x0a, 0x00, 0x00, 0x00, 0x00, 0x01}, 1111, nil
+}
+
+func newClient(ctx context.Context, t *testing.T) mappingplugin.Resolver {
+	server := grpc.NewServer()
+	mappingplugin.RegisterServer(server, fakeResolver{})
+
+	listener := bufconn.Listen(1024 * 1024)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	cc, err := grpc.DialContext(ctx, "bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cc.Close() })
+
+	return mappingplugin.NewClient(cc)
+}
+
+func TestClient_Resolve(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := newClient(ctx, t)
+
+	mac, vlan, err := client.Resolve(ctx, "pingpong", "id-1")
+	require.NoError(t, err)
+	require.Equal(t, "0a:00:00:00:00:01", mac.String())
+	require.Equal(t, int32(1111), vlan)
+
+	mac, vlan, err = client.Resolve(ctx, "pingpong", "id-2")
+	require.NoError(t, err)
+	require.Equal(t, "0a:00:00:00:00:02", mac.String())
+	require.Equal(t, int32(2222), vlan)
+
+	_, _, err = client.Resolve(ctx, "unknown", "id-1")
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestClient_Resolve_MalformedMAC(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, _, err := newClient(ctx, t).Resolve(ctx, "pingpong", "id-malformed")
+	require.Error(t, err)
+	require.ErrorIs(t, err, mappingplugin.ErrMalformedMapping)
+	require.Contains(t, err.Error(), "invalid MAC for pingpong")
+}
+
+func TestClient_Resolve_ReservedVLAN(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, _, err := newClient(ctx, t).Resolve(ctx, "pingpong", "id-vlan-4095")
+	require.ErrorIs(t, err, mappingplugin.ErrMalformedMapping)
+	require.Contains(t, err.Error(), "invalid VLAN for pingpong: 4095")
+}
+
+func TestTransportCredentials(t *testing.T) {
+	tlsConfig := new(tls.Config)
+
+	require.Equal(t, "insecure", mappingplugin.TransportCredentials("unix:///run/mapping.sock", tlsConfig).Info().SecurityProtocol)
+	require.Equal(t, "tls", mappingplugin.TransportCredentials("dns:///mapping:5000", tlsConfig).Info().SecurityProtocol)
+	require.Equal(t, "tls", mappingplugin.TransportCredentials("mapping:5000", tlsConfig).Info().SecurityProtocol)
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+)
+
+const mappingInfoGauge = "nse_service_mapping_info"
+
+// RegisterMappingInfo registers a gauge reporting 1 for every configured service labeled with its name, MAC and VLAN
+func RegisterMappingInfo(cfg *config.Config) error {
+	attributes := make([]attribute.Set, len(cfg.ServiceNames))
+	for i := range cfg.ServiceNames {
+		service := &cfg.ServiceNames[i]
+		attributes[i] = attribute.NewSet(
+			attribute.String("service", service.Name),
+			attribute.String("mac", service.MACAddr.String()),
+			attribute.String("vlan", strconv.FormatInt(int64(service.VLANTag), 10)),
+		)
+	}
+
+	_, err := otel.Meter(serviceInfoMeterName).Int64ObservableGauge(mappingInfoGauge,
+		metric.WithDescription("configured MAC and VLAN of supported network services, always 1"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			for i := range attributes {
+				observer.Observe(1, metric.WithAttributeSet(attributes[i]))
+			}
+			return nil
+		}),
+	)
+	return errors.Wrap(err, "failed to create mapping info gauge")
+}
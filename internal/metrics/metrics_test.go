@@ -0,0 +1,189 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/metrics"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/mapserver"
+)
+
+func TestPrometheusReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader, handler, err := metrics.NewPrometheusReader()
+	require.NoError(t, err)
+
+	meterProvider := metrics.NewMeterProvider(resource.Empty(), reader)
+	defer func() { _ = meterProvider.Shutdown(ctx) }()
+
+	server := mapserver.NewServer(&config.Config{
+		ServiceNames: []config.ServiceConfig{{Name: "pingpong"}},
+	})
+	conn, err := server.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             "id",
+			NetworkService: "pingpong",
+		},
+	})
+	require.NoError(t, err)
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `mapserver_requests_total{`)
+	require.Contains(t, recorder.Body.String(), `service="pingpong"`)
+	require.Contains(t, recorder.Body.String(), `mapserver_closes_total{`)
+}
+
+func TestRegisterServiceInfo(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := metrics.NewMeterProvider(resource.Empty(), reader)
+	defer func() { _ = meterProvider.Shutdown(ctx) }()
+
+	require.NoError(t, metrics.RegisterServiceInfo(&config.Config{
+		Payload: "ETHERNET",
+		ServiceNames: []config.ServiceConfig{
+			{Name: "pingpong@worker.domain"},
+			{Name: "pongping", Payload: "IP"},
+		},
+	}))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+
+	var services []string
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != "nse_service_info" {
+				continue
+			}
+			for _, point := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+				require.Equal(t, int64(1), point.Value)
+				service, _ := point.Attributes.Value("service")
+				domain, _ := point.Attributes.Value("domain")
+				payload, _ := point.Attributes.Value("payload")
+				services = append(services, service.AsString()+"@"+domain.AsString()+":"+payload.AsString())
+			}
+		}
+	}
+	require.ElementsMatch(t, []string{"pingpong@worker.domain:ETHERNET", "pongping@:IP"}, services)
+}
+
+func TestRegisterServiceInfo_EmptyDomain(t *testing.T) {
+	for name, sample := range map[string]struct {
+		emptyDomain string
+		domains     []string
+	}{
+		config.EmptyDomainKeep:        {emptyDomain: config.EmptyDomainKeep, domains: []string{"worker.domain", ""}},
+		config.EmptyDomainOmit:        {emptyDomain: config.EmptyDomainOmit, domains: []string{"worker.domain", "<none>"}},
+		config.EmptyDomainPlaceholder: {emptyDomain: config.EmptyDomainPlaceholder, domains: []string{"worker.domain", "default"}},
+	} {
+		sample := sample
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			reader := sdkmetric.NewManualReader()
+			meterProvider := metrics.NewMeterProvider(resource.Empty(), reader)
+			defer func() { _ = meterProvider.Shutdown(ctx) }()
+
+			require.NoError(t, metrics.RegisterServiceInfo(&config.Config{
+				ServiceInfoEmptyDomain: sample.emptyDomain,
+				ServiceInfoPlaceholder: "default",
+				ServiceNames: []config.ServiceConfig{
+					{Name: "pingpong@worker.domain"},
+					{Name: "pongping"},
+				},
+			}))
+
+			var rm metricdata.ResourceMetrics
+			require.NoError(t, reader.Collect(ctx, &rm))
+
+			var domains []string
+			for _, scope := range rm.ScopeMetrics {
+				for _, m := range scope.Metrics {
+					for _, point := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+						domain, ok := point.Attributes.Value("domain")
+						if !ok {
+							domains = append(domains, "<none>")
+							continue
+						}
+						domains = append(domains, domain.AsString())
+					}
+				}
+			}
+			require.ElementsMatch(t, sample.domains, domains)
+		})
+	}
+}
+
+func TestRegisterMappingInfo(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader, handler, err := metrics.NewPrometheusReader()
+	require.NoError(t, err)
+
+	meterProvider := metrics.NewMeterProvider(resource.Empty(), reader)
+	defer func() { _ = meterProvider.Shutdown(ctx) }()
+
+	require.NoError(t, metrics.RegisterMappingInfo(&config.Config{
+		ServiceNames: []config.ServiceConfig{
+			{Name: "pingpong@worker.domain", MACAddr: net.HardwareAddr{0x0a, 0x55, 0x44, 0x33, 0x22, 0x11}, VLANTag: 1111},
+			{Name: "pongping", MACAddr: net.HardwareAddr{0x0a, 0x55, 0x44, 0x33, 0x22, 0x22}, VLANTag: 2222},
+		},
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var lines []string
+	for _, line := range strings.Split(recorder.Body.String(), "\n") {
+		if strings.HasPrefix(line, "nse_service_mapping_info{") {
+			lines = append(lines, line)
+		}
+	}
+	require.Len(t, lines, 2)
+	require.Contains(t, recorder.Body.String(), `mac="0a:55:44:33:22:11"`)
+	require.Contains(t, recorder.Body.String(), `service="pingpong@worker.domain"`)
+	require.Contains(t, recorder.Body.String(), `vlan="1111"`)
+	require.Contains(t, recorder.Body.String(), `mac="0a:55:44:33:22:22"`)
+	require.Contains(t, recorder.Body.String(), `vlan="2222"`)
+}
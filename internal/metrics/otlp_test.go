@@ -0,0 +1,92 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/resource"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/metrics"
+)
+
+type fakeCollector struct {
+	colmetricpb.UnimplementedMetricsServiceServer
+	exports atomic.Int32
+}
+
+func (c *fakeCollector) Export(context.Context, *colmetricpb.ExportMetricsServiceRequest) (*colmetricpb.ExportMetricsServiceResponse, error) {
+	c.exports.Add(1)
+	return new(colmetricpb.ExportMetricsServiceResponse), nil
+}
+
+func startCollector(t *testing.T) (*fakeCollector, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	collector := new(fakeCollector)
+	server := grpc.NewServer()
+	colmetricpb.RegisterMetricsServiceServer(server, collector)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return collector, listener.Addr().String()
+}
+
+func TestOTLPReader_ExportInterval(t *testing.T) {
+	for name, sample := range map[string]struct {
+		interval time.Duration
+		exported bool
+	}{
+		"short": {interval: 20 * time.Millisecond, exported: true},
+		"long":  {interval: time.Hour, exported: false},
+	} {
+		sample := sample
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			collector, address := startCollector(t)
+
+			reader, err := metrics.NewOTLPReader(ctx, address, sample.interval)
+			require.NoError(t, err)
+
+			meterProvider := metrics.NewMeterProvider(resource.Empty(), reader)
+			counter, err := otel.Meter("test").Int64Counter("test_total")
+			require.NoError(t, err)
+			counter.Add(ctx, 1)
+
+			if sample.exported {
+				require.Eventually(t, func() bool { return collector.exports.Load() >= 2 }, time.Second, 10*time.Millisecond)
+			} else {
+				time.Sleep(100 * time.Millisecond)
+				require.Zero(t, collector.exports.Load())
+			}
+
+			// Shutdown exports the pending metrics whatever the interval is
+			_ = meterProvider.Shutdown(context.Background())
+		})
+	}
+}
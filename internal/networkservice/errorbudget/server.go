@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errorbudget provides chain element tracking consecutive Request failures of the next chain elements
+package errorbudget
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type errorBudgetServer struct {
+	threshold  int
+	onExceeded func()
+
+	mu       sync.Mutex
+	failures int
+	once     sync.Once
+}
+
+// NewServer returns a new chain element calling onExceeded once the next chain elements fail threshold Requests in a row.
+// Only failures of the endpoint itself are counted, Requests rejected because of the client are ignored.
+func NewServer(threshold int, onExceeded func()) networkservice.NetworkServiceServer {
+	return &errorBudgetServer{
+		threshold:  threshold,
+		onExceeded: onExceeded,
+	}
+}
+
+func (s *errorBudgetServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	conn, err := next.Server(ctx).Request(ctx, request)
+
+	if err != nil && !isEndpointFailure(err) {
+		return conn, err
+	}
+
+	s.mu.Lock()
+	if err != nil {
+		s.failures++
+	} else {
+		s.failures = 0
+	}
+	exceeded := s.failures >= s.threshold
+	s.mu.Unlock()
+
+	if exceeded {
+		s.once.Do(s.onExceeded)
+	}
+	return conn, err
+}
+
+func (s *errorBudgetServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+// isEndpointFailure returns true if err means the endpoint failed to serve the Request. Errors without a gRPC status
+// are Unknown and not counted: chain elements reject client requests with plain errors too.
+func isEndpointFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Internal, codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
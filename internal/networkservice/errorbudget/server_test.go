@@ -0,0 +1,129 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errorbudget_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/errorbudget"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/mapserver"
+)
+
+func TestErrorBudgetServer(t *testing.T) {
+	var exceeded int
+	server := chain.NewNetworkServiceServer(
+		errorbudget.NewServer(3, func() { exceeded++ }),
+		injecterror.NewServer(
+			injecterror.WithError(status.Error(codes.Internal, "endpoint failed")),
+			injecterror.WithRequestErrorTimes(0, 1, 3, 4, 5, 6),
+		),
+	)
+
+	request := func() error {
+		_, err := server.Request(context.Background(), &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{Id: "id"},
+		})
+		return err
+	}
+
+	// Success resets the consecutive failures
+	require.Error(t, request())
+	require.Error(t, request())
+	require.NoError(t, request())
+	require.Error(t, request())
+	require.Error(t, request())
+	require.Equal(t, 0, exceeded)
+
+	require.Error(t, request())
+	require.Equal(t, 1, exceeded)
+
+	require.Error(t, request())
+	require.Equal(t, 1, exceeded)
+}
+
+func TestErrorBudgetServer_ClientErrors(t *testing.T) {
+	var exceeded int
+	server := chain.NewNetworkServiceServer(
+		errorbudget.NewServer(2, func() { exceeded++ }),
+		injecterror.NewServer(
+			injecterror.WithError(status.Error(codes.Unavailable, "endpoint failed")),
+			injecterror.WithRequestErrorTimes(0, 4),
+		),
+		injecterror.NewServer(
+			injecterror.WithError(status.Error(codes.InvalidArgument, "invalid request")),
+			injecterror.WithRequestErrorTimes(0),
+		),
+		injecterror.NewServer(
+			injecterror.WithError(status.Error(codes.PermissionDenied, "permission denied")),
+			injecterror.WithRequestErrorTimes(0),
+		),
+		injecterror.NewServer(
+			injecterror.WithError(status.Error(codes.ResourceExhausted, "too many connections")),
+			injecterror.WithRequestErrorTimes(0),
+		),
+	)
+
+	request := func() error {
+		_, err := server.Request(context.Background(), &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{Id: "id"},
+		})
+		return err
+	}
+
+	// Client errors neither count nor reset the consecutive failures
+	require.Error(t, request())
+	require.Equal(t, codes.InvalidArgument, status.Code(request()))
+	require.Equal(t, codes.PermissionDenied, status.Code(request()))
+	require.Equal(t, codes.ResourceExhausted, status.Code(request()))
+	require.Equal(t, 0, exceeded)
+
+	require.Error(t, request())
+	require.Equal(t, 1, exceeded)
+}
+
+func TestErrorBudgetServer_UnsupportedService(t *testing.T) {
+	var exceeded int
+	server := chain.NewNetworkServiceServer(
+		errorbudget.NewServer(2, func() { exceeded++ }),
+		injecterror.NewServer(
+			injecterror.WithError(errors.New("plain rejection")),
+			injecterror.WithRequestErrorTimes(0, 1),
+		),
+		mapserver.NewServer(&config.Config{
+			ServiceNames: []config.ServiceConfig{{Name: "pingpong"}},
+		}),
+	)
+
+	for i := 0; i < 5; i++ {
+		_, err := server.Request(context.Background(), &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{Id: "id", NetworkService: "unknown"},
+		})
+		require.Error(t, err)
+	}
+	require.Equal(t, 0, exceeded)
+}
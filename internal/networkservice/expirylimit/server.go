@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package expirylimit provides chain element rejecting requests expecting longer lifetime than the endpoint grants
+package expirylimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type expiryLimitServer struct {
+	maxLifetime time.Duration
+}
+
+// NewServer returns a new chain element rejecting requests with InvalidArgument if the path segment of the
+// requesting client expires later than maxLifetime from now, instead of silently capping the connection lifetime
+func NewServer(maxLifetime time.Duration) networkservice.NetworkServiceServer {
+	return &expiryLimitServer{
+		maxLifetime: maxLifetime,
+	}
+}
+
+func (s *expiryLimitServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	path := request.GetConnection().GetPath()
+	if index := int(path.GetIndex()); index > 0 && index <= len(path.GetPathSegments()) {
+		if expires := path.GetPathSegments()[index-1].GetExpires(); expires != nil {
+			if lifetime := time.Until(expires.AsTime()); lifetime > s.maxLifetime {
+				return nil, status.Errorf(codes.InvalidArgument, "requested lifetime %v exceeds the maximum lifetime %v",
+					lifetime.Truncate(time.Second), s.maxLifetime)
+			}
+		}
+	}
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *expiryLimitServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
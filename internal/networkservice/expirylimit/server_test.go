@@ -0,0 +1,67 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expirylimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/expirylimit"
+)
+
+func newRequest(clientExpires time.Time) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "id",
+			Path: &networkservice.Path{
+				Index: 1,
+				PathSegments: []*networkservice.PathSegment{
+					{Name: "nsc", Expires: timestamppb.New(clientExpires)},
+					{Name: "vfio-server", Expires: timestamppb.New(time.Now().Add(10 * time.Minute))},
+				},
+			},
+		},
+	}
+}
+
+func TestExpiryLimitServer_WithinLimit(t *testing.T) {
+	_, err := expirylimit.NewServer(10*time.Minute).Request(context.Background(), newRequest(time.Now().Add(5*time.Minute)))
+	require.NoError(t, err)
+}
+
+func TestExpiryLimitServer_OverLimit(t *testing.T) {
+	_, err := expirylimit.NewServer(10*time.Minute).Request(context.Background(), newRequest(time.Now().Add(time.Hour)))
+	require.Error(t, err)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Contains(t, err.Error(), "exceeds the maximum lifetime 10m0s")
+}
+
+func TestExpiryLimitServer_NoClientSegment(t *testing.T) {
+	request := newRequest(time.Now().Add(time.Hour))
+	request.GetConnection().GetPath().Index = 0
+
+	_, err := expirylimit.NewServer(10*time.Minute).Request(context.Background(), request)
+	require.NoError(t, err)
+}
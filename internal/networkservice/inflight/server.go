@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package inflight provides chain element limiting the number of concurrently processed requests
+package inflight
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type inFlightServer struct {
+	slots chan struct{}
+}
+
+// NewServer returns a new chain element rejecting requests with ResourceExhausted if limit requests are already in flight
+func NewServer(limit int) networkservice.NetworkServiceServer {
+	return &inFlightServer{
+		slots: make(chan struct{}, limit),
+	}
+}
+
+func (s *inFlightServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests in flight: %d", cap(s.slots))
+	}
+	defer func() { <-s.slots }()
+
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *inFlightServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
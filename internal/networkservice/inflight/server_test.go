@@ -0,0 +1,91 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inflight_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/inflight"
+)
+
+type blockingServer struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.started <- struct{}{}
+	<-s.release
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *blockingServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func newRequest(id string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: id},
+	}
+}
+
+func TestInFlightServer(t *testing.T) {
+	const limit = 3
+
+	blocking := &blockingServer{
+		started: make(chan struct{}, limit),
+		release: make(chan struct{}),
+	}
+	server := chain.NewNetworkServiceServer(inflight.NewServer(limit), blocking)
+
+	errCh := make(chan error, limit)
+	for i := 0; i < limit; i++ {
+		go func(id string) {
+			_, err := server.Request(context.Background(), newRequest(id))
+			errCh <- err
+		}(fmt.Sprintf("id-%d", i))
+	}
+	for i := 0; i < limit; i++ {
+		<-blocking.started
+	}
+
+	_, err := server.Request(context.Background(), newRequest("id-excess"))
+	require.Error(t, err)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = server.Close(context.Background(), newRequest("id-0").GetConnection())
+	require.NoError(t, err)
+
+	close(blocking.release)
+	for i := 0; i < limit; i++ {
+		require.NoError(t, <-errCh)
+	}
+
+	_, err = server.Request(context.Background(), newRequest("id-excess"))
+	require.NoError(t, err)
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// checkAddrCount rejects connection with more allocated client addresses than the limit, 0 means unlimited
+func checkAddrCount(conn *networkservice.Connection, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	if count := len(conn.GetContext().GetIpContext().GetSrcIpAddrs()); count > limit {
+		return status.Errorf(codes.ResourceExhausted, "connection has %d addresses allocated, maximum is %d", count, limit)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+const (
+	// MACSourceKey is an extra context key of the way the destination MAC is obtained
+	MACSourceKey = "vfio-mac-source"
+	// VLANSourceKey is an extra context key of the way the VLAN tag is obtained
+	VLANSourceKey = "vfio-vlan-source"
+	// IPSourceKey is an extra context key of the way the client IP addresses are obtained
+	IPSourceKey = "vfio-ip-source"
+
+	// SourceStatic means the value is taken from the service config
+	SourceStatic = "static"
+	// SourcePlugin means the value is resolved by the mapping plugin
+	SourcePlugin = "plugin"
+	// SourceClient means the value is preserved from the client request
+	SourceClient = "client"
+	// SourceLabel means the VLAN is selected by the client label
+	SourceLabel = "label"
+	// SourcePool means the addresses are allocated by IPAM from the CIDR prefix pool
+	SourcePool = "pool"
+)
+
+// setAllocationSource records the ways the MAC, VLAN and client IP addresses are obtained in the connection extra
+// context. IPAM runs before the mapserver, so client addresses present at this point are allocated from the pool.
+func setAllocationSource(connContext *networkservice.ConnectionContext, macSource, vlanSource string) {
+	if connContext.GetExtraContext() == nil {
+		connContext.ExtraContext = make(map[string]string, 3)
+	}
+	connContext.GetExtraContext()[MACSourceKey] = macSource
+	connContext.GetExtraContext()[VLANSourceKey] = vlanSource
+	if len(connContext.GetIpContext().GetSrcIpAddrs()) > 0 {
+		connContext.GetExtraContext()[IPSourceKey] = SourcePool
+	} else {
+		delete(connContext.GetExtraContext(), IPSourceKey)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/opa"
+)
+
+// authorizePeer checks the peer SPIFFE ID is allowed to request the service, empty allowed list allows any peer
+func authorizePeer(ctx context.Context, service string, allowed []spiffeid.ID) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	id, err := peerSPIFFEID(ctx)
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, "failed to authorize peer to request %s: %s", service, err.Error())
+	}
+
+	for _, allowedID := range allowed {
+		if id == allowedID {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "peer %s is not allowed to request %s", id, service)
+}
+
+// peerSPIFFEID returns the SPIFFE ID of the peer TLS certificate
+func peerSPIFFEID(ctx context.Context) (spiffeid.ID, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return spiffeid.ID{}, errors.New("no peer identity")
+	}
+	cert := opa.ParseX509Cert(p.AuthInfo)
+	if cert == nil {
+		return spiffeid.ID{}, errors.New("no peer certificate")
+	}
+	id, err := x509svid.IDFromCert(cert)
+	if err != nil {
+		return spiffeid.ID{}, errors.Wrap(err, "invalid peer SPIFFE ID")
+	}
+	return id, nil
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// connections tracks connection IDs served per network service
+type connections struct {
+	mu           sync.Mutex
+	services     map[string]string
+	counts       map[string]int32
+	rejectChange bool
+	active       *atomic.Int64
+}
+
+func newConnections(rejectChange bool, active *atomic.Int64) *connections {
+	return &connections{
+		services:     make(map[string]string),
+		counts:       make(map[string]int32),
+		rejectChange: rejectChange,
+		active:       active,
+	}
+}
+
+// add stores the connection for the service, it returns the previous service of the connection and true if the
+// connection is new for the service. Connection known for another service is either rejected or moved to the service.
+func (c *connections) add(id, service string, limit int32) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev, ok := c.services[id]
+	switch {
+	case ok && prev == service:
+		return prev, false, nil
+	case ok && c.rejectChange:
+		return prev, false, status.Errorf(codes.FailedPrecondition, "connection %s is established for %s, can't be requested for %s", id, prev, service)
+	}
+	if limit > 0 && c.counts[service] >= limit {
+		return prev, false, status.Errorf(codes.ResourceExhausted, "connection limit is reached for %s: %d", service, limit)
+	}
+
+	if ok {
+		c.release(prev)
+	} else {
+		c.active.Add(1)
+	}
+	c.services[id] = service
+	c.counts[service]++
+
+	return prev, true, nil
+}
+
+// restore reverts add of the connection: it is moved back to the previous service or removed if there is none
+func (c *connections) restore(id, prev string) {
+	if prev == "" {
+		c.remove(id)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if service, ok := c.services[id]; ok {
+		c.release(service)
+	} else {
+		c.active.Add(1)
+	}
+	c.services[id] = prev
+	c.counts[prev]++
+}
+
+// remove deletes the connection, it returns false if the connection is unknown
+func (c *connections) remove(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	service, ok := c.services[id]
+	if !ok {
+		return false
+	}
+
+	delete(c.services, id)
+	c.release(service)
+	c.active.Add(-1)
+	return true
+}
+
+func (c *connections) release(service string) {
+	if c.counts[service]--; c.counts[service] == 0 {
+		delete(c.counts, service)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// checkContextSize rejects connection context with serialized size over the limit, 0 means unlimited
+func checkContextSize(conn *networkservice.Connection, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	if size := proto.Size(conn.GetContext()); size > limit {
+		return status.Errorf(codes.InvalidArgument, "connection context is too large: %d bytes, maximum is %d", size, limit)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// applyContextDefaults fills the connection context fields not set by the request from the defaults. Lists are only
+// filled if empty and maps only get the missing keys, so refreshes don't duplicate the defaults.
+func applyContextDefaults(conn *networkservice.Connection, defaults *networkservice.ConnectionContext) {
+	if defaults == nil {
+		return
+	}
+
+	if conn.GetContext() == nil {
+		conn.Context = new(networkservice.ConnectionContext)
+	}
+	fillUnset(conn.GetContext().ProtoReflect(), proto.Clone(defaults).ProtoReflect())
+}
+
+func fillUnset(dst, src protoreflect.Message) {
+	src.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			if dst.Get(fd).List().Len() == 0 {
+				list := dst.Mutable(fd).List()
+				for i := 0; i < value.List().Len(); i++ {
+					list.Append(value.List().Get(i))
+				}
+			}
+		case fd.IsMap():
+			m := dst.Mutable(fd).Map()
+			value.Map().Range(func(key protoreflect.MapKey, v protoreflect.Value) bool {
+				if !m.Has(key) {
+					m.Set(key, v)
+				}
+				return true
+			})
+		case fd.Message() != nil:
+			fillUnset(dst.Mutable(fd).Message(), value.Message())
+		case !dst.Has(fd):
+			dst.Set(fd, value)
+		}
+		return true
+	})
+}
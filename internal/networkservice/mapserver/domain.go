@@ -0,0 +1,34 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// checkDomain rejects the connection if its domain label doesn't match the service domain, missing label means no
+// domain
+func checkDomain(conn *networkservice.Connection, label, domain string) error {
+	if requested := conn.GetLabels()[label]; requested != domain {
+		return status.Errorf(codes.PermissionDenied, "domain %q of the request doesn't match domain %q of %s",
+			requested, domain, conn.GetNetworkService())
+	}
+	return nil
+}
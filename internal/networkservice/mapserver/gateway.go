@@ -0,0 +1,45 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"net"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+const (
+	ipv4DefaultRoute = "0.0.0.0/0"
+	ipv6DefaultRoute = "::/0"
+)
+
+// setGateway sets the gateway as the next hop of the client default route
+func setGateway(ipContext *networkservice.IPContext, prefix string, gateway net.IP) {
+	if gateway == nil {
+		return
+	}
+	for _, route := range ipContext.GetSrcRoutes() {
+		if route.GetPrefix() == prefix {
+			route.NextHop = gateway.String()
+			return
+		}
+	}
+	ipContext.SrcRoutes = append(ipContext.SrcRoutes, &networkservice.Route{
+		Prefix:  prefix,
+		NextHop: gateway.String(),
+	})
+}
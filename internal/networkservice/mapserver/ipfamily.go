@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"net"
+	"sort"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+)
+
+// orderByIPFamily moves addresses of the preferred IP family to the front keeping the allocation order within a family
+func orderByIPFamily(addrs []string, family string) []string {
+	if family != config.IPFamilyIPv4 && family != config.IPFamilyIPv6 {
+		return addrs
+	}
+
+	preferIPv4 := family == config.IPFamilyIPv4
+	sort.SliceStable(addrs, func(i, j int) bool {
+		return isIPv4(addrs[i]) == preferIPv4 && isIPv4(addrs[j]) != preferIPv4
+	})
+
+	return addrs
+}
+
+func isIPv4(addr string) bool {
+	ip, _, err := net.ParseCIDR(addr)
+	if err != nil {
+		ip = net.ParseIP(addr)
+	}
+	return ip.To4() != nil
+}
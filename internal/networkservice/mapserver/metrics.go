@@ -0,0 +1,68 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	meterName       = "mapserver"
+	serviceKey      = "service"
+	peerKey         = "peer_spiffe_id"
+	requestsCounter = "mapserver_requests"
+	closesCounter   = "mapserver_closes"
+)
+
+type serverMetrics struct {
+	requests metric.Int64Counter
+	closes   metric.Int64Counter
+}
+
+func newServerMetrics() *serverMetrics {
+	meter := otel.Meter(meterName)
+	return &serverMetrics{
+		requests: newCounter(meter, requestsCounter, "number of handled requests"),
+		closes:   newCounter(meter, closesCounter, "number of handled closes"),
+	}
+}
+
+func newCounter(meter metric.Meter, name, description string) metric.Int64Counter {
+	counter, err := meter.Int64Counter(name, metric.WithDescription(description))
+	if err != nil {
+		return noop.Int64Counter{}
+	}
+	return counter
+}
+
+// addRequest counts the request of the service, the peer SPIFFE ID is added as an attribute if it is set
+func (m *serverMetrics) addRequest(ctx context.Context, service, peerID string) {
+	attributes := []attribute.KeyValue{attribute.String(serviceKey, service)}
+	if peerID != "" {
+		attributes = append(attributes, attribute.String(peerKey, peerID))
+	}
+	m.requests.Add(ctx, 1, metric.WithAttributes(attributes...))
+}
+
+func (m *serverMetrics) addClose(ctx context.Context, service string) {
+	m.closes.Add(ctx, 1, metric.WithAttributes(attribute.String(serviceKey, service)))
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/mappingplugin"
+)
+
+// Option is an option pattern for NewServer
+type Option func(s *mapServer)
+
+// WithStats sets stats counting served requests and closes
+func WithStats(stats *Stats) Option {
+	return func(s *mapServer) {
+		s.stats = stats
+	}
+}
+
+// WithResolver sets resolver deciding MAC and VLAN of the connections, the configured mapping is used if it
+// fails or doesn't respond within timeout
+func WithResolver(resolver mappingplugin.Resolver, timeout time.Duration) Option {
+	return func(s *mapServer) {
+		s.resolver = resolver
+		s.resolveTimeout = timeout
+	}
+}
+
+// WithPayloadContextDefaults sets connection context defaults of services keyed by their payload, request and service
+// mapping take precedence over them
+func WithPayloadContextDefaults(defaults map[string]*networkservice.ConnectionContext) Option {
+	return func(s *mapServer) {
+		s.payloadDefaults = defaults
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+const (
+	// PathServiceKey is a path segment metrics key of the network service mapped by the endpoint
+	PathServiceKey = "vfio-service"
+	// PathMappedAtKey is a path segment metrics key of the RFC 3339 time the connection was mapped at
+	PathMappedAtKey = "vfio-mapped-at"
+)
+
+// setPathMetadata records the mapped service and time in the metrics of the endpoint path segment
+func setPathMetadata(conn *networkservice.Connection, now time.Time) {
+	segments := conn.GetPath().GetPathSegments()
+	index := int(conn.GetPath().GetIndex())
+	if index >= len(segments) {
+		return
+	}
+
+	segment := segments[index]
+	if segment.GetMetrics() == nil {
+		segment.Metrics = make(map[string]string, 2)
+	}
+	segment.GetMetrics()[PathServiceKey] = conn.GetNetworkService()
+	segment.GetMetrics()[PathMappedAtKey] = now.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"net"
+)
+
+// setPrefixLength frames allocated IPv4 addresses with the prefix length dropping duplicates, 0 keeps addresses unchanged
+func setPrefixLength(addrs []string, prefixLen int32) []string {
+	if prefixLen == 0 {
+		return addrs
+	}
+
+	result := addrs[:0]
+	seen := make(map[string]struct{}, len(addrs))
+	for _, addr := range addrs {
+		if ip, _, err := net.ParseCIDR(addr); err == nil && ip.To4() != nil {
+			addr = (&net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(int(prefixLen), net.IPv4len*8)}).String()
+		}
+		if _, ok := seen[addr]; ok {
+			continue
+		}
+		seen[addr] = struct{}{}
+		result = append(result, addr)
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// isRetryable returns true for errors of the momentarily unavailable mapping plugin
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// resolveWithRetries calls the resolver retrying transient failures with exponential backoff, every call is limited
+// by the resolve timeout
+func (s *mapServer) resolveWithRetries(ctx context.Context, service, connID string) (net.HardwareAddr, int32, error) {
+	backoff := s.retryBackoff
+	for attempt := 0; ; attempt++ {
+		macAddr, vlanTag, err := s.resolveOnce(ctx, service, connID)
+		if err == nil || attempt >= s.retries || !isRetryable(err) {
+			return macAddr, vlanTag, err
+		}
+		log.FromContext(ctx).Debugf("retrying mapping plugin call for %s after transient failure: %s", connID, err.Error())
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, 0, err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (s *mapServer) resolveOnce(ctx context.Context, service, connID string) (net.HardwareAddr, int32, error) {
+	resolveCtx, cancel := context.WithTimeout(ctx, s.resolveTimeout)
+	defer cancel()
+
+	return s.resolver.Resolve(resolveCtx, service, connID)
+}
@@ -1,5 +1,7 @@
 // Copyright (c) 2020-2022 Doc.ai and/or its affiliates.
 //
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
 // SPDX-License-Identifier: Apache-2.0
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -20,64 +22,343 @@ package mapserver
 import (
 	"context"
 	"net"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
 
 	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/mappingplugin"
 )
 
 type mapServer struct {
-	entries map[string]*entry
+	entries           map[string]*entry
+	preferredIPFamily string
+	validateContext   bool
+	disallowUntagged  bool
+	preserveClientMAC bool
+	pathMetadata      bool
+	allocationSource  bool
+	peerIDTelemetry   bool
+	domainLabel       string
+	extraContext      map[string]string
+	stripFields       []protoreflect.FieldDescriptor
+	maxContextSize    int
+	maxAddrs          int
+	retries           int
+	retryBackoff      time.Duration
+	metrics           *serverMetrics
+	stats             *Stats
+	payloadDefaults   map[string]*networkservice.ConnectionContext
+	resolver          mappingplugin.Resolver
+	resolveTimeout    time.Duration
+	strictResolver    bool
+	connections       *connections
 }
 
 type entry struct {
-	macAddr net.HardwareAddr
-	vlanTag int32
+	defaults    *networkservice.ConnectionContext
+	macAddr     net.HardwareAddr
+	vlanTag     int32
+	vlanLabel   string
+	vlans       map[string]int32
+	allowedIDs  []spiffeid.ID
+	domain      string
+	labels      map[string]string
+	ipv4Gateway net.IP
+	ipv6Gateway net.IP
+	maxConns    int32
+	passthrough bool
+	prefixLen   int32
+	mtu         uint32
+	macSource   string
+	vlanSource  string
+	log         bool
+	sampler     sdktrace.Sampler
 }
 
 // NewServer returns a new `network service -> { MAC, VLAN }` mapping server chain element
-func NewServer(cfg *config.Config) networkservice.NetworkServiceServer {
+func NewServer(cfg *config.Config, opts ...Option) networkservice.NetworkServiceServer {
 	s := &mapServer{
-		entries: make(map[string]*entry, len(cfg.ServiceNames)),
+		entries:           make(map[string]*entry, len(cfg.ServiceNames)),
+		preferredIPFamily: cfg.PreferredIPFamily,
+		validateContext:   cfg.ValidateContext,
+		disallowUntagged:  cfg.DisallowUntagged,
+		preserveClientMAC: cfg.PreserveClientMAC,
+		pathMetadata:      cfg.PathSegmentMetadata,
+		allocationSource:  cfg.AllocationSourceContext,
+		peerIDTelemetry:   cfg.PeerIDTelemetry,
+		domainLabel:       cfg.DomainMatchLabel,
+		extraContext:      cfg.ExtraConnectionContext,
+		stripFields:       contextFields(cfg.StripContextFields),
+		maxContextSize:    cfg.MaxContextSize,
+		maxAddrs:          cfg.MaxAddrsPerConnection,
+		retries:           cfg.MappingPluginRetries,
+		retryBackoff:      cfg.MappingPluginRetryBackoff,
+		strictResolver:    cfg.MappingPluginStrict,
+		metrics:           newServerMetrics(),
+		stats:             new(Stats),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	s.connections = newConnections(cfg.ServiceChangePolicy == config.ServiceChangeReject, &s.stats.active)
 
 	for i := range cfg.ServiceNames {
 		service := &cfg.ServiceNames[i]
 		s.entries[service.Name] = &entry{
-			macAddr: service.MACAddr,
-			vlanTag: service.VLANTag,
+			defaults:    s.payloadDefaults[cfg.ServicePayload(service)],
+			macAddr:     service.MACAddr,
+			vlanTag:     service.VLANTag,
+			vlanLabel:   service.VLANLabel,
+			vlans:       service.VLANsByLabel,
+			allowedIDs:  service.AllowedSPIFFEIDs,
+			domain:      cfg.ServiceDomain(service),
+			labels:      cfg.ServiceLabels(service),
+			ipv4Gateway: service.IPv4Gateway,
+			ipv6Gateway: service.IPv6Gateway,
+			maxConns:    service.MaxConnections,
+			passthrough: service.Passthrough,
+			prefixLen:   service.PrefixLength,
+			mtu:         service.MTU,
+			macSource:   SourceStatic,
+			vlanSource:  SourceStatic,
+			log:         service.Log,
+		}
+		if service.SampleRatio != nil {
+			s.entries[service.Name].sampler = sdktrace.TraceIDRatioBased(*service.SampleRatio)
 		}
 	}
 
 	return s
 }
 
-func (s *mapServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+func (s *mapServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (conn *networkservice.Connection, err error) {
+	ctx, span := startSpan(ctx, s.sampler(request.GetConnection().GetNetworkService()), requestSpanName, request.GetConnection().GetId(), request.GetConnection().GetNetworkService())
+	defer func() { endSpan(span, err) }()
+
+	var peerID string
+	if s.peerIDTelemetry {
+		if id, idErr := peerSPIFFEID(ctx); idErr == nil {
+			peerID = id.String()
+			setPeerID(span, peerID)
+		}
+	}
+
+	return s.request(ctx, request, peerID)
+}
+
+func (s *mapServer) request(ctx context.Context, request *networkservice.NetworkServiceRequest, peerID string) (*networkservice.Connection, error) {
 	conn := request.GetConnection()
 
+	if conn.GetNetworkService() == "" {
+		return nil, status.Error(codes.InvalidArgument, "network service is not set")
+	}
+
+	if err := checkContextSize(conn, s.maxContextSize); err != nil {
+		return nil, err
+	}
+	if err := checkAddrCount(conn, s.maxAddrs); err != nil {
+		return nil, err
+	}
+
 	entry, ok := s.entries[conn.GetNetworkService()]
 	if !ok {
-		return nil, errors.Errorf("network service is not supported: %s", conn.GetNetworkService())
+		return nil, status.Errorf(codes.NotFound, "network service is not supported: %s", conn.GetNetworkService())
+	}
+	if err := authorizePeer(ctx, conn.GetNetworkService(), entry.allowedIDs); err != nil {
+		return nil, err
+	}
+	if s.domainLabel != "" {
+		if err := checkDomain(conn, s.domainLabel, entry.domain); err != nil {
+			return nil, err
+		}
+	}
+	s.metrics.addRequest(ctx, conn.GetNetworkService(), peerID)
+	s.stats.requests.Add(1)
+
+	prevService, isNew, err := s.connections.add(conn.GetId(), conn.GetNetworkService(), entry.maxConns)
+	if err != nil {
+		return nil, err
+	}
+	// A failed Request keeps the connection as it was: unknown or established for the previous service
+	restore := func() {
+		if isNew {
+			s.connections.restore(conn.GetId(), prevService)
+		}
+	}
+
+	entry = entry.selectVLAN(conn.GetLabels())
+
+	if len(entry.labels) > 0 && conn.GetLabels() == nil {
+		conn.Labels = make(map[string]string, len(entry.labels))
+	}
+	for key, value := range entry.labels {
+		conn.Labels[key] = value
+	}
+
+	if !entry.passthrough {
+		resolved, resolveErr := s.resolve(ctx, conn, entry)
+		if resolveErr != nil {
+			restore()
+			return nil, resolveErr
+		}
+		if s.disallowUntagged {
+			if err = checkTagged(conn.GetNetworkService(), resolved.vlanTag); err != nil {
+				restore()
+				return nil, err
+			}
+		}
+		stripContext(conn, s.stripFields)
+		applyContextDefaults(conn, resolved.defaults)
+		s.setContext(conn, resolved)
+		if s.validateContext {
+			if err = validateEthernetContext(conn.GetContext().GetEthernetContext()); err != nil {
+				restore()
+				return nil, err
+			}
+		}
+	}
+
+	if s.pathMetadata {
+		setPathMetadata(conn, time.Now())
+	}
+
+	conn, err = next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		restore()
+	}
+	if entry.log {
+		logRequest(ctx, request.GetConnection(), conn, err)
 	}
+	return conn, err
+}
 
+func (s *mapServer) setContext(conn *networkservice.Connection, entry *entry) {
 	if conn.GetContext() == nil {
 		conn.Context = new(networkservice.ConnectionContext)
 	}
 	if conn.GetContext().GetEthernetContext() == nil {
 		conn.GetContext().EthernetContext = new(networkservice.EthernetContext)
 	}
+	if len(s.extraContext) > 0 && conn.GetContext().GetExtraContext() == nil {
+		conn.GetContext().ExtraContext = make(map[string]string, len(s.extraContext))
+	}
+	for key, value := range s.extraContext {
+		conn.GetContext().GetExtraContext()[key] = value
+	}
+
 	ethernetContext := conn.GetContext().GetEthernetContext()
 
-	ethernetContext.DstMac = entry.macAddr.String()
+	macSource := SourceClient
+	if !s.preserveClientMAC || ethernetContext.GetDstMac() == "" {
+		ethernetContext.DstMac = entry.macAddr.String()
+		macSource = entry.macSource
+	}
 	ethernetContext.VlanTag = entry.vlanTag
+	if s.allocationSource {
+		setAllocationSource(conn.GetContext(), macSource, entry.vlanSource)
+	}
+
+	if entry.mtu > 0 && (conn.GetContext().GetMTU() == 0 || conn.GetContext().GetMTU() > entry.mtu) {
+		conn.GetContext().MTU = entry.mtu
+	}
+
+	if entry.ipv4Gateway != nil || entry.ipv6Gateway != nil {
+		if conn.GetContext().GetIpContext() == nil {
+			conn.GetContext().IpContext = new(networkservice.IPContext)
+		}
+		setGateway(conn.GetContext().GetIpContext(), ipv4DefaultRoute, entry.ipv4Gateway)
+		setGateway(conn.GetContext().GetIpContext(), ipv6DefaultRoute, entry.ipv6Gateway)
+	}
+
+	if ipContext := conn.GetContext().GetIpContext(); ipContext != nil {
+		ipContext.SrcIpAddrs = orderByIPFamily(setPrefixLength(ipContext.GetSrcIpAddrs(), entry.prefixLen), s.preferredIPFamily)
+		ipContext.DstIpAddrs = orderByIPFamily(setPrefixLength(ipContext.GetDstIpAddrs(), entry.prefixLen), s.preferredIPFamily)
+	}
+}
+
+// selectVLAN returns entry with VLAN selected by the client label if it matches
+func (e *entry) selectVLAN(labels map[string]string) *entry {
+	if e.vlanLabel == "" {
+		return e
+	}
+	vlanTag, ok := e.vlans[labels[e.vlanLabel]]
+	if !ok {
+		return e
+	}
 
-	return next.Server(ctx).Request(ctx, request)
+	selected := *e
+	selected.vlanTag = vlanTag
+	selected.vlanSource = SourceLabel
+	return &selected
 }
 
-func (s *mapServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+// resolve returns entry with MAC and VLAN decided by the resolver if it is set, malformed mapping fails the request
+// with Internal in the strict mode
+func (s *mapServer) resolve(ctx context.Context, conn *networkservice.Connection, entry *entry) (*entry, error) {
+	if s.resolver == nil {
+		return entry, nil
+	}
+
+	macAddr, vlanTag, err := s.resolveWithRetries(ctx, conn.GetNetworkService(), conn.GetId())
+	if err == nil && len(macAddr) == 0 {
+		err = errors.Wrapf(mappingplugin.ErrMalformedMapping, "plugin returned empty MAC for %s", conn.GetNetworkService())
+	}
+	if err != nil {
+		if s.strictResolver && errors.Is(err, mappingplugin.ErrMalformedMapping) {
+			return nil, status.Errorf(codes.Internal, "mapping plugin: %s", err.Error())
+		}
+		log.FromContext(ctx).Warnf("using configured mapping of %s: %s", conn.GetNetworkService(), err.Error())
+		return entry, nil
+	}
+
+	resolved := *entry
+	resolved.macAddr = macAddr
+	resolved.vlanTag = vlanTag
+	resolved.macSource = SourcePlugin
+	resolved.vlanSource = SourcePlugin
+	return &resolved, nil
+}
+
+func logRequest(ctx context.Context, requested, conn *networkservice.Connection, err error) {
+	if err != nil {
+		log.FromContext(ctx).Infof("request %s for %s failed: %s", requested.GetId(), requested.GetNetworkService(), err.Error())
+		return
+	}
+	log.FromContext(ctx).Infof("request %s for %s: mac %s, vlan %d, src ips %v, dst ips %v",
+		conn.GetId(), conn.GetNetworkService(),
+		conn.GetContext().GetEthernetContext().GetDstMac(), conn.GetContext().GetEthernetContext().GetVlanTag(),
+		conn.GetContext().GetIpContext().GetSrcIpAddrs(), conn.GetContext().GetIpContext().GetDstIpAddrs())
+}
+
+func (s *mapServer) sampler(service string) sdktrace.Sampler {
+	if entry, ok := s.entries[service]; ok {
+		return entry.sampler
+	}
+	return nil
+}
+
+func (s *mapServer) Close(ctx context.Context, conn *networkservice.Connection) (_ *empty.Empty, err error) {
+	ctx, span := startSpan(ctx, s.sampler(conn.GetNetworkService()), closeSpanName, conn.GetId(), conn.GetNetworkService())
+	defer func() { endSpan(span, err) }()
+
+	s.metrics.addClose(ctx, conn.GetNetworkService())
+	s.stats.closes.Add(1)
+	if entry, ok := s.entries[conn.GetNetworkService()]; ok && entry.log {
+		log.FromContext(ctx).Infof("close %s for %s", conn.GetId(), conn.GetNetworkService())
+	}
+	if !s.connections.remove(conn.GetId()) {
+		log.FromContext(ctx).Debugf("closing unknown connection: %s", conn.GetId())
+	}
 	return next.Server(ctx).Close(ctx, conn)
 }
@@ -0,0 +1,1162 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/groupipam"
+	"github.com/networkservicemesh/sdk/pkg/tools/cidr"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/log/logruslogger"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/mappingplugin"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/mapserver"
+)
+
+const serviceName = "pingpong"
+
+func newConfig() *config.Config {
+	return &config.Config{
+		PreferredIPFamily: config.IPFamilyBoth,
+		ServiceNames: []config.ServiceConfig{
+			{
+				Name:    serviceName,
+				MACAddr: net.HardwareAddr{0x0a, 0x55, 0x44, 0x33, 0x22, 0x11},
+				VLANTag: 1111,
+			},
+		},
+	}
+}
+
+func newRequest() *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             "id",
+			NetworkService: serviceName,
+		},
+	}
+}
+
+func TestMapServer_Request(t *testing.T) {
+	conn, err := mapserver.NewServer(newConfig()).Request(context.Background(), newRequest())
+	require.NoError(t, err)
+
+	require.Equal(t, "0a:55:44:33:22:11", conn.GetContext().GetEthernetContext().GetDstMac())
+	require.Equal(t, int32(1111), conn.GetContext().GetEthernetContext().GetVlanTag())
+}
+
+func TestMapServer_PreserveClientMAC(t *testing.T) {
+	newClientRequest := func(mac string) *networkservice.NetworkServiceRequest {
+		request := newRequest()
+		request.GetConnection().Context = &networkservice.ConnectionContext{
+			EthernetContext: &networkservice.EthernetContext{DstMac: mac},
+		}
+		return request
+	}
+
+	cfg := newConfig()
+
+	conn, err := mapserver.NewServer(cfg).Request(context.Background(), newClientRequest("0a:00:00:00:00:01"))
+	require.NoError(t, err)
+	require.Equal(t, "0a:55:44:33:22:11", conn.GetContext().GetEthernetContext().GetDstMac())
+
+	cfg.PreserveClientMAC = true
+
+	conn, err = mapserver.NewServer(cfg).Request(context.Background(), newClientRequest("0a:00:00:00:00:01"))
+	require.NoError(t, err)
+	require.Equal(t, "0a:00:00:00:00:01", conn.GetContext().GetEthernetContext().GetDstMac())
+	require.Equal(t, int32(1111), conn.GetContext().GetEthernetContext().GetVlanTag())
+
+	conn, err = mapserver.NewServer(cfg).Request(context.Background(), newClientRequest(""))
+	require.NoError(t, err)
+	require.Equal(t, "0a:55:44:33:22:11", conn.GetContext().GetEthernetContext().GetDstMac())
+}
+
+func TestMapServer_Log(t *testing.T) {
+	hook := test.NewGlobal()
+	ctx := log.WithLog(context.Background(), logruslogger.New(context.Background()))
+
+	cfg := newConfig()
+	cfg.ServiceNames[0].Log = true
+	cfg.ServiceNames = append(cfg.ServiceNames, config.ServiceConfig{
+		Name: "quiet",
+	})
+	server := mapserver.NewServer(cfg)
+
+	for _, service := range []string{serviceName, "quiet"} {
+		request := newRequest()
+		request.GetConnection().Id = service
+		request.GetConnection().NetworkService = service
+
+		conn, err := server.Request(ctx, request)
+		require.NoError(t, err)
+		_, err = server.Close(ctx, conn)
+		require.NoError(t, err)
+	}
+
+	entries := hook.AllEntries()
+	require.Len(t, entries, 2)
+	require.Contains(t, entries[0].Message, "request pingpong for pingpong: mac 0a:55:44:33:22:11, vlan 1111")
+	require.Equal(t, "close pingpong for pingpong", entries[1].Message)
+}
+
+func TestMapServer_Request_UnknownService(t *testing.T) {
+	request := newRequest()
+	request.GetConnection().NetworkService = "unknown"
+
+	_, err := mapserver.NewServer(newConfig()).Request(context.Background(), request)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestMapServer_Request_EmptyService(t *testing.T) {
+	request := newRequest()
+	request.GetConnection().NetworkService = ""
+
+	_, err := mapserver.NewServer(newConfig()).Request(context.Background(), request)
+	require.Error(t, err)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestMapServer_ValidateContext(t *testing.T) {
+	cfg := newConfig()
+	cfg.ValidateContext = true
+	cfg.ServiceNames[0].MACAddr = nil
+
+	_, err := mapserver.NewServer(cfg).Request(context.Background(), newRequest())
+	require.Error(t, err)
+	require.Equal(t, codes.Internal, status.Code(err))
+
+	cfg = newConfig()
+	cfg.ValidateContext = true
+	cfg.ServiceNames[0].VLANTag = 5000
+
+	_, err = mapserver.NewServer(cfg).Request(context.Background(), newRequest())
+	require.Error(t, err)
+	require.Equal(t, codes.Internal, status.Code(err))
+
+	cfg.ValidateContext = false
+
+	_, err = mapserver.NewServer(cfg).Request(context.Background(), newRequest())
+	require.NoError(t, err)
+}
+
+func TestMapServer_DisallowUntagged(t *testing.T) {
+	cfg := newConfig()
+	cfg.DisallowUntagged = true
+
+	conn, err := mapserver.NewServer(cfg).Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	require.Equal(t, int32(1111), conn.GetContext().GetEthernetContext().GetVlanTag())
+
+	cfg.ServiceNames[0].VLANTag = 0
+
+	_, err = mapserver.NewServer(cfg).Request(context.Background(), newRequest())
+	require.Error(t, err)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestMapServer_MaxContextSize(t *testing.T) {
+	cfg := newConfig()
+	cfg.MaxContextSize = 128
+
+	newContextRequest := func(size int) *networkservice.NetworkServiceRequest {
+		request := newRequest()
+		request.GetConnection().Context = &networkservice.ConnectionContext{
+			ExtraContext: map[string]string{"key": strings.Repeat("v", size)},
+		}
+		return request
+	}
+
+	_, err := mapserver.NewServer(cfg).Request(context.Background(), newContextRequest(64))
+	require.NoError(t, err)
+
+	_, err = mapserver.NewServer(cfg).Request(context.Background(), newContextRequest(256))
+	require.Error(t, err)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestMapServer_MaxAddrsPerConnection(t *testing.T) {
+	cfg := newConfig()
+	cfg.MaxAddrsPerConnection = 2
+
+	newAddrsRequest := func(addrs ...string) *networkservice.NetworkServiceRequest {
+		request := newRequest()
+		request.GetConnection().Context = &networkservice.ConnectionContext{
+			IpContext: &networkservice.IPContext{SrcIpAddrs: addrs},
+		}
+		return request
+	}
+
+	_, err := mapserver.NewServer(cfg).Request(context.Background(), newAddrsRequest("172.16.0.1/32", "fd00::1/128"))
+	require.NoError(t, err)
+
+	_, err = mapserver.NewServer(cfg).Request(context.Background(), newAddrsRequest("172.16.0.1/32", "fd00::1/128", "172.17.0.1/32"))
+	require.Error(t, err)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestMapServer_ServiceNamePrefix(t *testing.T) {
+	t.Setenv("NSM_SERVICE_NAMES", serviceName+": { vlan: 1111 }")
+	t.Setenv("NSM_SERVICE_NAME_PREFIX", "tenant-")
+
+	cfg := new(config.Config)
+	require.NoError(t, cfg.Process())
+
+	request := newRequest()
+	request.GetConnection().NetworkService = "tenant-" + serviceName
+
+	conn, err := mapserver.NewServer(cfg).Request(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, int32(1111), conn.GetContext().GetEthernetContext().GetVlanTag())
+
+	_, err = mapserver.NewServer(cfg).Request(context.Background(), newRequest())
+	require.Error(t, err)
+}
+
+func TestMapServer_MaxConnections(t *testing.T) {
+	cfg := newConfig()
+	cfg.ServiceNames[0].MaxConnections = 1
+	cfg.ServiceNames = append(cfg.ServiceNames, config.ServiceConfig{
+		Name: "unlimited",
+	})
+
+	server := mapserver.NewServer(cfg)
+
+	newServiceRequest := func(id, service string) *networkservice.NetworkServiceRequest {
+		request := newRequest()
+		request.GetConnection().Id = id
+		request.GetConnection().NetworkService = service
+		return request
+	}
+
+	conn, err := server.Request(context.Background(), newServiceRequest("id-1", serviceName))
+	require.NoError(t, err)
+
+	// Refresh of the existing connection is not limited
+	_, err = server.Request(context.Background(), &networkservice.NetworkServiceRequest{Connection: conn.Clone()})
+	require.NoError(t, err)
+
+	_, err = server.Request(context.Background(), newServiceRequest("id-2", serviceName))
+	require.Error(t, err)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// Other services are not affected
+	for _, id := range []string{"id-2", "id-3"} {
+		_, err = server.Request(context.Background(), newServiceRequest(id, "unlimited"))
+		require.NoError(t, err)
+	}
+
+	_, err = server.Close(context.Background(), conn)
+	require.NoError(t, err)
+
+	_, err = server.Request(context.Background(), newServiceRequest("id-4", serviceName))
+	require.NoError(t, err)
+}
+
+func TestMapServer_DomainMatch(t *testing.T) {
+	cfg := newConfig()
+	cfg.DomainMatchLabel = "domain"
+	cfg.ServiceNames = append(cfg.ServiceNames, config.ServiceConfig{
+		Name:    "pingpong@worker.domain",
+		VLANTag: 2222,
+	})
+
+	server := mapserver.NewServer(cfg)
+
+	request := newRequest()
+	request.GetConnection().NetworkService = "pingpong@worker.domain"
+	request.GetConnection().Labels = map[string]string{"domain": "worker.domain"}
+	conn, err := server.Request(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, int32(2222), conn.GetContext().GetEthernetContext().GetVlanTag())
+
+	request = newRequest()
+	request.GetConnection().Id = "id-2"
+	request.GetConnection().NetworkService = "pingpong@worker.domain"
+	request.GetConnection().Labels = map[string]string{"domain": "master.domain"}
+	_, err = server.Request(context.Background(), request)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Missing label matches services without domain only
+	request.GetConnection().Labels = nil
+	_, err = server.Request(context.Background(), request)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = server.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+}
+
+func TestMapServer_DefaultDomain(t *testing.T) {
+	cfg := newConfig()
+	cfg.DefaultDomain = "worker.domain"
+	cfg.DomainMatchLabel = "domain"
+
+	server := mapserver.NewServer(cfg)
+
+	// The bare service name is still served, its domain is the default one
+	request := newRequest()
+	request.GetConnection().Labels = map[string]string{"domain": "worker.domain"}
+	conn, err := server.Request(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, serviceName, conn.GetNetworkService())
+	require.Equal(t, int32(1111), conn.GetContext().GetEthernetContext().GetVlanTag())
+
+	request = newRequest()
+	request.GetConnection().Id = "id-2"
+	_, err = server.Request(context.Background(), request)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestMapServer_ServiceChange_Remap(t *testing.T) {
+	cfg := newConfig()
+	cfg.ServiceChangePolicy = config.ServiceChangeRemap
+	cfg.ServiceNames[0].MaxConnections = 1
+	cfg.ServiceNames = append(cfg.ServiceNames, config.ServiceConfig{
+		Name:    "other",
+		VLANTag: 2222,
+	})
+
+	server := mapserver.NewServer(cfg)
+
+	_, err := server.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+
+	request := newRequest()
+	request.GetConnection().NetworkService = "other"
+	conn, err := server.Request(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, int32(2222), conn.GetContext().GetEthernetContext().GetVlanTag())
+
+	// The connection slot of the previous service is released
+	request = newRequest()
+	request.GetConnection().Id = "id-2"
+	_, err = server.Request(context.Background(), request)
+	require.NoError(t, err)
+}
+
+func TestMapServer_ServiceChange_RemapFailed(t *testing.T) {
+	cfg := newConfig()
+	cfg.ServiceChangePolicy = config.ServiceChangeRemap
+	cfg.DisallowUntagged = true
+	cfg.ServiceNames[0].MaxConnections = 1
+	cfg.ServiceNames = append(cfg.ServiceNames, config.ServiceConfig{
+		Name: "untagged",
+	})
+
+	server := mapserver.NewServer(cfg)
+
+	conn, err := server.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+
+	request := newRequest()
+	request.GetConnection().NetworkService = "untagged"
+	_, err = server.Request(context.Background(), request)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// The connection is still established for the previous service and holds its slot
+	request = newRequest()
+	request.GetConnection().Id = "id-2"
+	_, err = server.Request(context.Background(), request)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = server.Close(context.Background(), conn)
+	require.NoError(t, err)
+
+	_, err = server.Request(context.Background(), request)
+	require.NoError(t, err)
+}
+
+func TestMapServer_ServiceChange_Reject(t *testing.T) {
+	cfg := newConfig()
+	cfg.ServiceChangePolicy = config.ServiceChangeReject
+	cfg.ServiceNames = append(cfg.ServiceNames, config.ServiceConfig{
+		Name:    "other",
+		VLANTag: 2222,
+	})
+
+	server := mapserver.NewServer(cfg)
+
+	conn, err := server.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+
+	request := newRequest()
+	request.GetConnection().NetworkService = "other"
+	_, err = server.Request(context.Background(), request)
+	require.Error(t, err)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// Refresh of the connection for the same service is not affected
+	conn, err = server.Request(context.Background(), &networkservice.NetworkServiceRequest{Connection: conn.Clone()})
+	require.NoError(t, err)
+	require.Equal(t, int32(1111), conn.GetContext().GetEthernetContext().GetVlanTag())
+}
+
+func TestMapServer_PathSegmentMetadata(t *testing.T) {
+	newPathRequest := func() *networkservice.NetworkServiceRequest {
+		request := newRequest()
+		request.GetConnection().Path = &networkservice.Path{
+			Index: 1,
+			PathSegments: []*networkservice.PathSegment{
+				{Name: "nsmgr"},
+				{Name: "vfio-server"},
+			},
+		}
+		return request
+	}
+
+	cfg := newConfig()
+
+	conn, err := mapserver.NewServer(cfg).Request(context.Background(), newPathRequest())
+	require.NoError(t, err)
+	require.Empty(t, conn.GetPath().GetPathSegments()[1].GetMetrics())
+
+	cfg.PathSegmentMetadata = true
+
+	before := time.Now()
+	conn, err = mapserver.NewServer(cfg).Request(context.Background(), newPathRequest())
+	require.NoError(t, err)
+
+	require.Empty(t, conn.GetPath().GetPathSegments()[0].GetMetrics())
+
+	segment := conn.GetPath().GetPathSegments()[1]
+	require.Equal(t, "vfio-server", segment.GetName())
+	require.Equal(t, serviceName, segment.GetMetrics()[mapserver.PathServiceKey])
+
+	mappedAt, err := time.Parse(time.RFC3339Nano, segment.GetMetrics()[mapserver.PathMappedAtKey])
+	require.NoError(t, err)
+	require.False(t, mappedAt.Before(before))
+}
+
+func TestMapServer_Close_UnknownConnection(t *testing.T) {
+	cfg := newConfig()
+	cfg.ServiceNames[0].MaxConnections = 1
+
+	server := mapserver.NewServer(cfg)
+
+	conn, err := server.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+
+	unknown := conn.Clone()
+	unknown.Id = "unknown"
+	for i := 0; i < 2; i++ {
+		_, err = server.Close(context.Background(), unknown)
+		require.NoError(t, err)
+	}
+
+	// Closing unknown connections doesn't release the limit
+	request := newRequest()
+	request.GetConnection().Id = "id-2"
+	_, err = server.Request(context.Background(), request)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	for i := 0; i < 2; i++ {
+		_, err = server.Close(context.Background(), conn)
+		require.NoError(t, err)
+	}
+
+	_, err = server.Request(context.Background(), request)
+	require.NoError(t, err)
+}
+
+func TestMapServer_Passthrough(t *testing.T) {
+	cfg := newConfig()
+	cfg.ServiceNames[0].Passthrough = true
+	cfg.ServiceNames[0].IPv4Gateway = net.ParseIP("172.16.0.1")
+
+	conn, err := mapserver.NewServer(cfg).Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	require.Nil(t, conn.GetContext())
+}
+
+func TestMapServer_Labels(t *testing.T) {
+	cfg := newConfig()
+	cfg.Labels = map[string]string{
+		"app":  "vfio",
+		"zone": "a",
+	}
+
+	request := newRequest()
+	request.GetConnection().Labels = map[string]string{
+		"app":    "client",
+		"tenant": "blue",
+	}
+
+	conn, err := mapserver.NewServer(cfg).Request(context.Background(), request)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]string{
+		"app":    "vfio",
+		"zone":   "a",
+		"tenant": "blue",
+	}, conn.GetLabels())
+}
+
+func TestMapServer_VLANsByLabel(t *testing.T) {
+	cfg := newConfig()
+	cfg.ServiceNames[0].VLANLabel = "tenant"
+	cfg.ServiceNames[0].VLANsByLabel = map[string]int32{
+		"red":  100,
+		"blue": 200,
+	}
+
+	newTenantRequest := func(labels map[string]string) *networkservice.NetworkServiceRequest {
+		request := newRequest()
+		request.GetConnection().Labels = labels
+		return request
+	}
+
+	for name, sample := range map[string]struct {
+		labels  map[string]string
+		vlanTag int32
+	}{
+		"matched":   {labels: map[string]string{"tenant": "blue"}, vlanTag: 200},
+		"unmatched": {labels: map[string]string{"tenant": "green"}, vlanTag: 1111},
+		"no label":  {labels: map[string]string{"app": "client"}, vlanTag: 1111},
+	} {
+		sample := sample
+		t.Run(name, func(t *testing.T) {
+			conn, err := mapserver.NewServer(cfg).Request(context.Background(), newTenantRequest(sample.labels))
+			require.NoError(t, err)
+			require.Equal(t, sample.vlanTag, conn.GetContext().GetEthernetContext().GetVlanTag())
+			require.Equal(t, "0a:55:44:33:22:11", conn.GetContext().GetEthernetContext().GetDstMac())
+		})
+	}
+}
+
+func TestMapServer_ExtraConnectionContext(t *testing.T) {
+	cfg := newConfig()
+	cfg.ExtraConnectionContext = map[string]string{
+		"team": "dataplane",
+		"tier": "gold",
+	}
+
+	request := newRequest()
+	request.GetConnection().Context = &networkservice.ConnectionContext{
+		ExtraContext: map[string]string{
+			"client": "value",
+			"tier":   "silver",
+		},
+	}
+
+	conn, err := mapserver.NewServer(cfg).Request(context.Background(), request)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]string{
+		"client": "value",
+		"team":   "dataplane",
+		"tier":   "gold",
+	}, conn.GetContext().GetExtraContext())
+}
+
+func TestMapServer_StripContextFields(t *testing.T) {
+	cfg := newConfig()
+	cfg.StripContextFields = []string{"dns_context", "MTU"}
+
+	request := newRequest()
+	request.GetConnection().Context = &networkservice.ConnectionContext{
+		DnsContext: &networkservice.DNSContext{
+			Configs: []*networkservice.DNSConfig{{DnsServerIps: []string{"8.8.8.8"}}},
+		},
+		MTU:          1500,
+		ExtraContext: map[string]string{"team": "dataplane"},
+	}
+
+	conn, err := mapserver.NewServer(cfg).Request(context.Background(), request)
+	require.NoError(t, err)
+
+	require.Nil(t, conn.GetContext().GetDnsContext())
+	require.Zero(t, conn.GetContext().GetMTU())
+	require.Equal(t, map[string]string{"team": "dataplane"}, conn.GetContext().GetExtraContext())
+	require.Equal(t, int32(1111), conn.GetContext().GetEthernetContext().GetVlanTag())
+}
+
+func TestMapServer_MTU(t *testing.T) {
+	for name, sample := range map[string]struct {
+		clientMTU, serviceMTU, expected uint32
+	}{
+		"client lower":    {clientMTU: 1400, serviceMTU: 1500, expected: 1400},
+		"service lower":   {clientMTU: 9000, serviceMTU: 1500, expected: 1500},
+		"equal":           {clientMTU: 1500, serviceMTU: 1500, expected: 1500},
+		"client not set":  {clientMTU: 0, serviceMTU: 1500, expected: 1500},
+		"service not set": {clientMTU: 9000, serviceMTU: 0, expected: 9000},
+	} {
+		sample := sample
+		t.Run(name, func(t *testing.T) {
+			cfg := newConfig()
+			cfg.ServiceNames[0].MTU = sample.serviceMTU
+
+			request := newRequest()
+			request.GetConnection().Context = &networkservice.ConnectionContext{MTU: sample.clientMTU}
+
+			conn, err := mapserver.NewServer(cfg).Request(context.Background(), request)
+			require.NoError(t, err)
+			require.Equal(t, sample.expected, conn.GetContext().GetMTU())
+		})
+	}
+}
+
+func TestMapServer_PayloadContextDefaults(t *testing.T) {
+	cfg := newConfig()
+	cfg.Payload = "ETHERNET"
+	cfg.ServiceNames = append(cfg.ServiceNames, config.ServiceConfig{
+		Name:    "pongping",
+		Payload: "IP",
+		MTU:     1400,
+	})
+	server := mapserver.NewServer(cfg, mapserver.WithPayloadContextDefaults(map[string]*networkservice.ConnectionContext{
+		"ETHERNET": {
+			MTU:             9000,
+			ExtraContext:    map[string]string{"tier": "gold", "team": "dataplane"},
+			EthernetContext: &networkservice.EthernetContext{VlanTag: 5},
+		},
+		"IP": {
+			MTU:          9000,
+			ExtraContext: map[string]string{"tier": "bronze"},
+		},
+	}))
+
+	// Request overrides payload defaults, service mapping overrides both
+	request := newRequest()
+	request.GetConnection().Context = &networkservice.ConnectionContext{
+		ExtraContext: map[string]string{"tier": "silver"},
+	}
+	conn, err := server.Request(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, uint32(9000), conn.GetContext().GetMTU())
+	require.Equal(t, map[string]string{"tier": "silver", "team": "dataplane"}, conn.GetContext().GetExtraContext())
+	require.Equal(t, int32(1111), conn.GetContext().GetEthernetContext().GetVlanTag())
+
+	request = newRequest()
+	request.GetConnection().Id = "id-2"
+	request.GetConnection().NetworkService = "pongping"
+	conn, err = server.Request(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1400), conn.GetContext().GetMTU())
+	require.Equal(t, map[string]string{"tier": "bronze"}, conn.GetContext().GetExtraContext())
+}
+
+func TestMapServer_PayloadContextDefaults_Refresh(t *testing.T) {
+	cfg := newConfig()
+	cfg.Payload = "ETHERNET"
+	server := mapserver.NewServer(cfg, mapserver.WithPayloadContextDefaults(map[string]*networkservice.ConnectionContext{
+		"ETHERNET": {
+			DnsContext: &networkservice.DNSContext{
+				Configs: []*networkservice.DNSConfig{{DnsServerIps: []string{"10.0.0.53"}}},
+			},
+			IpContext: &networkservice.IPContext{
+				ExcludedPrefixes: []string{"10.96.0.0/12"},
+			},
+		},
+	}))
+
+	request := newRequest()
+	request.GetConnection().Context = &networkservice.ConnectionContext{
+		IpContext: &networkservice.IPContext{SrcIpAddrs: []string{"169.254.0.1/32"}},
+	}
+	conn, err := server.Request(context.Background(), request)
+	require.NoError(t, err)
+
+	// Refreshes must not duplicate the defaults
+	for i := 0; i < 3; i++ {
+		conn, err = server.Request(context.Background(), &networkservice.NetworkServiceRequest{Connection: conn})
+		require.NoError(t, err)
+	}
+
+	require.Len(t, conn.GetContext().GetDnsContext().GetConfigs(), 1)
+	require.Equal(t, []string{"10.0.0.53"}, conn.GetContext().GetDnsContext().GetConfigs()[0].GetDnsServerIps())
+	require.Equal(t, []string{"10.96.0.0/12"}, conn.GetContext().GetIpContext().GetExcludedPrefixes())
+	require.Equal(t, []string{"169.254.0.1/32"}, conn.GetContext().GetIpContext().GetSrcIpAddrs())
+}
+
+func TestMapServer_Gateway(t *testing.T) {
+	cfg := newConfig()
+	cfg.ServiceNames[0].IPv4Gateway = net.ParseIP("172.16.0.1")
+	cfg.ServiceNames[0].IPv6Gateway = net.ParseIP("fd00::1")
+
+	server := mapserver.NewServer(cfg)
+
+	conn, err := server.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+
+	// Refresh must not duplicate the routes
+	conn, err = server.Request(context.Background(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	require.Equal(t, []*networkservice.Route{
+		{
+			Prefix:  "0.0.0.0/0",
+			NextHop: "172.16.0.1",
+		},
+		{
+			Prefix:  "::/0",
+			NextHop: "fd00::1",
+		},
+	}, conn.GetContext().GetIpContext().GetSrcRoutes())
+}
+
+func TestMapServer_Spans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func(tracerProvider trace.TracerProvider) { otel.SetTracerProvider(tracerProvider) }(otel.GetTracerProvider())
+	otel.SetTracerProvider(tracerProvider)
+
+	server := mapserver.NewServer(newConfig())
+
+	conn, err := server.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	_, err = server.Close(context.Background(), conn)
+	require.NoError(t, err)
+
+	request := newRequest()
+	request.GetConnection().NetworkService = "unknown"
+	_, err = server.Request(context.Background(), request)
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+
+	require.Equal(t, "mapserver.Request", spans[0].Name)
+	require.Equal(t, otelcodes.Unset, spans[0].Status.Code)
+	require.Equal(t, "mapserver.Close", spans[1].Name)
+	require.Equal(t, "mapserver.Request", spans[2].Name)
+	require.Equal(t, otelcodes.Error, spans[2].Status.Code)
+	require.Len(t, spans[2].Events, 1)
+}
+
+func TestMapServer_SampleRatio(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func(tracerProvider trace.TracerProvider) { otel.SetTracerProvider(tracerProvider) }(otel.GetTracerProvider())
+	otel.SetTracerProvider(tracerProvider)
+
+	never, always := 0.0, 1.0
+
+	cfg := newConfig()
+	cfg.ServiceNames[0].SampleRatio = &never
+	cfg.ServiceNames = append(cfg.ServiceNames, config.ServiceConfig{
+		Name:        "sampled",
+		SampleRatio: &always,
+	})
+	server := mapserver.NewServer(cfg)
+
+	for _, service := range []string{serviceName, "sampled"} {
+		request := newRequest()
+		request.GetConnection().Id = service
+		request.GetConnection().NetworkService = service
+
+		conn, err := server.Request(context.Background(), request)
+		require.NoError(t, err)
+		_, err = server.Close(context.Background(), conn)
+		require.NoError(t, err)
+	}
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		require.Contains(t, span.Attributes, attribute.String("network.service", "sampled"))
+	}
+}
+
+func TestMapServer_PreferredIPFamily(t *testing.T) {
+	_, ipv4Net, err := net.ParseCIDR("172.16.0.0/24")
+	require.NoError(t, err)
+	_, ipv6Net, err := net.ParseCIDR("fd00::/120")
+	require.NoError(t, err)
+	groups := cidr.Groups{{ipv4Net}, {ipv6Net}}
+
+	for family, isIPv4 := range map[string]bool{
+		config.IPFamilyIPv4: true,
+		config.IPFamilyIPv6: false,
+		config.IPFamilyBoth: true,
+	} {
+		t.Run(family, func(t *testing.T) {
+			cfg := newConfig()
+			cfg.PreferredIPFamily = family
+
+			server := chain.NewNetworkServiceServer(
+				groupipam.NewServer(groups),
+				mapserver.NewServer(cfg),
+			)
+
+			conn, err := server.Request(context.Background(), newRequest())
+			require.NoError(t, err)
+
+			srcIPAddrs := conn.GetContext().GetIpContext().GetSrcIpAddrs()
+			require.Len(t, srcIPAddrs, 2)
+
+			ip, _, err := net.ParseCIDR(srcIPAddrs[0])
+			require.NoError(t, err)
+			require.Equal(t, isIPv4, ip.To4() != nil)
+		})
+	}
+}
+
+func TestMapServer_PrefixLength(t *testing.T) {
+	_, ipv4Net, err := net.ParseCIDR("172.16.0.0/24")
+	require.NoError(t, err)
+	_, ipv6Net, err := net.ParseCIDR("fd00::/120")
+	require.NoError(t, err)
+	groups := cidr.Groups{{ipv4Net}, {ipv6Net}}
+
+	for prefixLen, expected := range map[int32]string{
+		0:  "/32",
+		30: "/30",
+		24: "/24",
+	} {
+		cfg := newConfig()
+		cfg.ServiceNames[0].PrefixLength = prefixLen
+
+		server := chain.NewNetworkServiceServer(
+			groupipam.NewServer(groups),
+			mapserver.NewServer(cfg),
+		)
+
+		conn, err := server.Request(context.Background(), newRequest())
+		require.NoError(t, err)
+
+		// Refresh doesn't duplicate reframed addresses
+		conn, err = server.Request(context.Background(), &networkservice.NetworkServiceRequest{Connection: conn.Clone()})
+		require.NoError(t, err)
+
+		ipContext := conn.GetContext().GetIpContext()
+		for _, addrs := range [][]string{ipContext.GetSrcIpAddrs(), ipContext.GetDstIpAddrs()} {
+			require.Len(t, addrs, 2)
+			require.True(t, strings.HasSuffix(addrs[0], expected), addrs[0])
+			require.True(t, strings.HasSuffix(addrs[1], "/128"), addrs[1])
+		}
+	}
+}
+
+type flakyResolver struct {
+	errs  []error
+	calls int
+}
+
+func (r *flakyResolver) Resolve(context.Context, string, string) (net.HardwareAddr, int32, error) {
+	r.calls++
+	if len(r.errs) > 0 {
+		err := r.errs[0]
+		r.errs = r.errs[1:]
+		return nil, 0, err
+	}
+	return net.HardwareAddr{0x0a, 0x00, 0x00, 0x00, 0x00, 0x01}, 2222, nil
+}
+
+func TestMapServer_ResolverRetry(t *testing.T) {
+	cfg := newConfig()
+	cfg.MappingPluginRetries = 2
+	cfg.MappingPluginRetryBackoff = time.Millisecond
+
+	resolver := &flakyResolver{errs: []error{
+		status.Error(codes.Unavailable, "busy"),
+		status.Error(codes.Aborted, "busy"),
+	}}
+	conn, err := mapserver.NewServer(cfg, mapserver.WithResolver(resolver, time.Second)).
+		Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	require.Equal(t, 3, resolver.calls)
+	require.Equal(t, "0a:00:00:00:00:01", conn.GetContext().GetEthernetContext().GetDstMac())
+}
+
+func TestMapServer_ResolverRetry_Permanent(t *testing.T) {
+	cfg := newConfig()
+	cfg.MappingPluginRetries = 2
+	cfg.MappingPluginRetryBackoff = time.Millisecond
+
+	// The configured mapping is used once the plugin fails without retries
+	resolver := &flakyResolver{errs: []error{
+		status.Error(codes.NotFound, "unknown service"),
+	}}
+	conn, err := mapserver.NewServer(cfg, mapserver.WithResolver(resolver, time.Second)).
+		Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	require.Equal(t, 1, resolver.calls)
+	require.Equal(t, "0a:55:44:33:22:11", conn.GetContext().GetEthernetContext().GetDstMac())
+
+	resolver = &flakyResolver{errs: []error{
+		status.Error(codes.Unavailable, "busy"),
+		status.Error(codes.Unavailable, "busy"),
+		status.Error(codes.Unavailable, "busy"),
+	}}
+	conn, err = mapserver.NewServer(cfg, mapserver.WithResolver(resolver, time.Second)).
+		Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	require.Equal(t, 3, resolver.calls)
+	require.Equal(t, "0a:55:44:33:22:11", conn.GetContext().GetEthernetContext().GetDstMac())
+}
+
+type fakeResolver struct {
+	err     error
+	macAddr net.HardwareAddr
+}
+
+func (r *fakeResolver) Resolve(_ context.Context, _, _ string) (net.HardwareAddr, int32, error) {
+	if r.err != nil {
+		return nil, 0, r.err
+	}
+	if r.macAddr != nil {
+		return r.macAddr, 2222, nil
+	}
+	return net.HardwareAddr{0x0a, 0x00, 0x00, 0x00, 0x00, 0x01}, 2222, nil
+}
+
+func TestMapServer_Resolver(t *testing.T) {
+	conn, err := mapserver.NewServer(newConfig(), mapserver.WithResolver(new(fakeResolver), time.Second)).
+		Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	require.Equal(t, "0a:00:00:00:00:01", conn.GetContext().GetEthernetContext().GetDstMac())
+	require.Equal(t, int32(2222), conn.GetContext().GetEthernetContext().GetVlanTag())
+
+	// Configured mapping is used if the resolver fails
+	resolver := &fakeResolver{err: status.Error(codes.Unavailable, "plugin is down")}
+	conn, err = mapserver.NewServer(newConfig(), mapserver.WithResolver(resolver, time.Second)).
+		Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	require.Equal(t, "0a:55:44:33:22:11", conn.GetContext().GetEthernetContext().GetDstMac())
+	require.Equal(t, int32(1111), conn.GetContext().GetEthernetContext().GetVlanTag())
+}
+
+func TestMapServer_Resolver_MalformedMAC(t *testing.T) {
+	for name, resolver := range map[string]*fakeResolver{
+		"invalid": {err: errors.Wrap(mappingplugin.ErrMalformedMapping, "plugin returned invalid MAC for pingpong")},
+		"empty":   {macAddr: net.HardwareAddr{}},
+	} {
+		resolver := resolver
+		t.Run(name, func(t *testing.T) {
+			cfg := newConfig()
+
+			// Configured mapping is used by default
+			conn, err := mapserver.NewServer(cfg, mapserver.WithResolver(resolver, time.Second)).
+				Request(context.Background(), newRequest())
+			require.NoError(t, err)
+			require.Equal(t, "0a:55:44:33:22:11", conn.GetContext().GetEthernetContext().GetDstMac())
+
+			cfg.MappingPluginStrict = true
+
+			_, err = mapserver.NewServer(cfg, mapserver.WithResolver(resolver, time.Second)).
+				Request(context.Background(), newRequest())
+			require.Error(t, err)
+			require.Equal(t, codes.Internal, status.Code(err))
+			require.Contains(t, err.Error(), "mapping plugin")
+		})
+	}
+}
+
+func TestMapServer_AllocationSourceContext(t *testing.T) {
+	for name, sample := range map[string]struct {
+		preserveClientMAC bool
+		clientMAC         string
+		labels            map[string]string
+		srcIPAddrs        []string
+		resolver          *fakeResolver
+		macSource         string
+		vlanSource        string
+		ipSource          string
+	}{
+		"static": {
+			macSource:  mapserver.SourceStatic,
+			vlanSource: mapserver.SourceStatic,
+		},
+		"plugin": {
+			resolver:   new(fakeResolver),
+			macSource:  mapserver.SourcePlugin,
+			vlanSource: mapserver.SourcePlugin,
+		},
+		"plugin failure": {
+			resolver:   &fakeResolver{err: status.Error(codes.Unavailable, "plugin is down")},
+			macSource:  mapserver.SourceStatic,
+			vlanSource: mapserver.SourceStatic,
+		},
+		"client": {
+			preserveClientMAC: true,
+			clientMAC:         "0a:00:00:00:00:02",
+			macSource:         mapserver.SourceClient,
+			vlanSource:        mapserver.SourceStatic,
+		},
+		"label": {
+			labels:     map[string]string{"tier": "gold"},
+			macSource:  mapserver.SourceStatic,
+			vlanSource: mapserver.SourceLabel,
+		},
+		"pool": {
+			srcIPAddrs: []string{"169.254.0.1/32"},
+			macSource:  mapserver.SourceStatic,
+			vlanSource: mapserver.SourceStatic,
+			ipSource:   mapserver.SourcePool,
+		},
+	} {
+		sample := sample
+		t.Run(name, func(t *testing.T) {
+			cfg := newConfig()
+			cfg.AllocationSourceContext = true
+			cfg.PreserveClientMAC = sample.preserveClientMAC
+			cfg.ServiceNames[0].VLANLabel = "tier"
+			cfg.ServiceNames[0].VLANsByLabel = map[string]int32{"gold": 100}
+
+			var opts []mapserver.Option
+			if sample.resolver != nil {
+				opts = append(opts, mapserver.WithResolver(sample.resolver, time.Second))
+			}
+
+			request := newRequest()
+			request.GetConnection().Labels = sample.labels
+			request.GetConnection().Context = &networkservice.ConnectionContext{
+				EthernetContext: &networkservice.EthernetContext{DstMac: sample.clientMAC},
+				IpContext:       &networkservice.IPContext{SrcIpAddrs: sample.srcIPAddrs},
+			}
+
+			conn, err := mapserver.NewServer(cfg, opts...).Request(context.Background(), request)
+			require.NoError(t, err)
+			require.Equal(t, sample.macSource, conn.GetContext().GetExtraContext()[mapserver.MACSourceKey])
+			require.Equal(t, sample.vlanSource, conn.GetContext().GetExtraContext()[mapserver.VLANSourceKey])
+			require.Equal(t, sample.ipSource, conn.GetContext().GetExtraContext()[mapserver.IPSourceKey])
+		})
+	}
+
+	conn, err := mapserver.NewServer(newConfig()).Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	require.NotContains(t, conn.GetContext().GetExtraContext(), mapserver.MACSourceKey)
+	require.NotContains(t, conn.GetContext().GetExtraContext(), mapserver.VLANSourceKey)
+	require.NotContains(t, conn.GetContext().GetExtraContext(), mapserver.IPSourceKey)
+}
+
+func withPeerSPIFFEID(t *testing.T, id string) context.Context {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{spiffeid.RequireFromString(id).URL()},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+		},
+	})
+}
+
+func TestMapServer_AllowedSPIFFEIDs(t *testing.T) {
+	cfg := newConfig()
+	cfg.ServiceNames[0].AllowedSPIFFEIDs = []spiffeid.ID{
+		spiffeid.RequireFromString("spiffe://example.org/forwarder"),
+		spiffeid.RequireFromString("spiffe://example.org/nsmgr"),
+	}
+	server := mapserver.NewServer(cfg)
+
+	_, err := server.Request(withPeerSPIFFEID(t, "spiffe://example.org/nsmgr"), newRequest())
+	require.NoError(t, err)
+
+	_, err = server.Request(withPeerSPIFFEID(t, "spiffe://example.org/intruder"), newRequest())
+	require.Error(t, err)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = server.Request(context.Background(), newRequest())
+	require.Error(t, err)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Services without the allowed list accept any peer
+	_, err = mapserver.NewServer(newConfig()).Request(withPeerSPIFFEID(t, "spiffe://example.org/intruder"), newRequest())
+	require.NoError(t, err)
+}
+
+func TestMapServer_PeerIDTelemetry(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func(tracerProvider trace.TracerProvider) { otel.SetTracerProvider(tracerProvider) }(otel.GetTracerProvider())
+	otel.SetTracerProvider(tracerProvider)
+
+	reader := sdkmetric.NewManualReader()
+	defer func(meterProvider metric.MeterProvider) { otel.SetMeterProvider(meterProvider) }(otel.GetMeterProvider())
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	cfg := newConfig()
+	cfg.PeerIDTelemetry = true
+
+	_, err := mapserver.NewServer(cfg).Request(withPeerSPIFFEID(t, "spiffe://example.org/nsmgr"), newRequest())
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Contains(t, spans[0].Attributes, attribute.String("peer.spiffe_id", "spiffe://example.org/nsmgr"))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	var peerIDs []string
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != "mapserver_requests" {
+				continue
+			}
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				peerID, _ := point.Attributes.Value("peer_spiffe_id")
+				peerIDs = append(peerIDs, peerID.AsString())
+			}
+		}
+	}
+	require.Equal(t, []string{"spiffe://example.org/nsmgr"}, peerIDs)
+
+	// Peer SPIFFE ID is not recorded by default
+	exporter.Reset()
+	_, err = mapserver.NewServer(newConfig()).Request(withPeerSPIFFEID(t, "spiffe://example.org/nsmgr"), newRequest())
+	require.NoError(t, err)
+
+	spans = exporter.GetSpans()
+	require.Len(t, spans, 1)
+	for _, kv := range spans[0].Attributes {
+		require.NotEqual(t, attribute.Key("peer.spiffe_id"), kv.Key)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import "sync/atomic"
+
+// Stats counts requests, closes and active connections of the supported network services
+type Stats struct {
+	requests atomic.Int64
+	closes   atomic.Int64
+	active   atomic.Int64
+}
+
+// Requests returns the number of requests to the supported network services
+func (s *Stats) Requests() int64 {
+	return s.requests.Load()
+}
+
+// Closes returns the number of closes
+func (s *Stats) Closes() int64 {
+	return s.closes.Load()
+}
+
+// Active returns the number of active connections
+func (s *Stats) Active() int64 {
+	return s.active.Load()
+}
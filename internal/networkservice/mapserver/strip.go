@@ -0,0 +1,48 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// contextFields returns descriptors of the connection context fields with the proto names, unknown names are skipped
+func contextFields(names []string) []protoreflect.FieldDescriptor {
+	descriptors := new(networkservice.ConnectionContext).ProtoReflect().Descriptor().Fields()
+
+	var fields []protoreflect.FieldDescriptor
+	for _, name := range names {
+		if field := descriptors.ByName(protoreflect.Name(name)); field != nil {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// stripContext clears the fields of the connection context
+func stripContext(conn *networkservice.Connection, fields []protoreflect.FieldDescriptor) {
+	if conn.GetContext() == nil {
+		return
+	}
+
+	message := conn.GetContext().ProtoReflect()
+	for _, field := range fields {
+		message.Clear(field)
+	}
+}
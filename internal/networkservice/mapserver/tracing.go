@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"context"
+	"crypto/rand"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName      = "mapserver"
+	requestSpanName = "mapserver.Request"
+	closeSpanName   = "mapserver.Close"
+	connectionIDKey = "connection.id"
+	networkSvcKey   = "network.service"
+	peerIDKey       = "peer.spiffe_id"
+)
+
+// startSpan starts a new span unless the per-service sampler drops it, nil sampler doesn't drop anything
+func startSpan(ctx context.Context, sampler sdktrace.Sampler, name, connID, service string) (context.Context, trace.Span) {
+	if sampler != nil && !isSampled(ctx, sampler, name) {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(
+		attribute.String(connectionIDKey, connID),
+		attribute.String(networkSvcKey, service),
+	))
+}
+
+func isSampled(ctx context.Context, sampler sdktrace.Sampler, name string) bool {
+	traceID := trace.SpanContextFromContext(ctx).TraceID()
+	if !traceID.IsValid() {
+		_, _ = rand.Read(traceID[:])
+	}
+	result := sampler.ShouldSample(sdktrace.SamplingParameters{
+		ParentContext: ctx,
+		TraceID:       traceID,
+		Name:          name,
+		Kind:          trace.SpanKindInternal,
+	})
+	return result.Decision != sdktrace.Drop
+}
+
+func setPeerID(span trace.Span, peerID string) {
+	span.SetAttributes(attribute.String(peerIDKey, peerID))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapserver
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+const maxVLANTag = 4094
+
+// validateEthernetContext checks that the ethernet context is internally consistent
+func validateEthernetContext(ethernetContext *networkservice.EthernetContext) error {
+	vlanTag := ethernetContext.GetVlanTag()
+	if vlanTag < 0 || vlanTag > maxVLANTag {
+		return status.Errorf(codes.Internal, "invalid ethernet context: VLAN tag %d is out of range [0, %d]", vlanTag, maxVLANTag)
+	}
+	if vlanTag != 0 && ethernetContext.GetDstMac() == "" {
+		return status.Errorf(codes.Internal, "invalid ethernet context: VLAN tag %d is set without destination MAC", vlanTag)
+	}
+	return nil
+}
+
+// checkTagged rejects the untagged VLAN resolved for the service
+func checkTagged(service string, vlanTag int32) error {
+	if vlanTag == 0 {
+		return status.Errorf(codes.FailedPrecondition, "untagged VLAN is not allowed for %s", service)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mechanismcheck provides chain element rejecting requests without any mechanism
+package mechanismcheck
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type mechanismCheckServer struct {
+	supported string
+}
+
+// NewServer returns a new chain element rejecting requests with neither selected nor preferred mechanism
+func NewServer(supported ...string) networkservice.NetworkServiceServer {
+	return &mechanismCheckServer{
+		supported: strings.Join(supported, ", "),
+	}
+}
+
+func (s *mechanismCheckServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if request.GetConnection().GetMechanism() == nil && len(request.GetMechanismPreferences()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "no mechanism is selected or preferred, supported mechanisms: %s", s.supported)
+	}
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *mechanismCheckServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package peervalidity provides chain element rejecting requests from peers with near-expiry certificates
+package peervalidity
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/opa"
+)
+
+type peerValidityServer struct {
+	minValidity time.Duration
+}
+
+// NewServer returns a new chain element rejecting requests if the peer certificate expires in less than minValidity,
+// requests without a peer certificate are left to the authorize server
+func NewServer(minValidity time.Duration) networkservice.NetworkServiceServer {
+	return &peerValidityServer{
+		minValidity: minValidity,
+	}
+}
+
+func (s *peerValidityServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		if cert := opa.ParseX509Cert(p.AuthInfo); cert != nil {
+			if remaining := time.Until(cert.NotAfter); remaining < s.minValidity {
+				return nil, status.Errorf(codes.PermissionDenied, "peer certificate expires in %v, minimum remaining validity is %v",
+					remaining.Truncate(time.Second), s.minValidity)
+			}
+		}
+	}
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *peerValidityServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package readiness provides chain element rejecting requests until the endpoint is initialized
+package readiness
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+// Gate is closed until Open is called
+type Gate struct {
+	open atomic.Bool
+}
+
+// Open marks the endpoint as initialized
+func (g *Gate) Open() {
+	g.open.Store(true)
+}
+
+// Close marks the endpoint as not initialized again
+func (g *Gate) Close() {
+	g.open.Store(false)
+}
+
+// IsOpen returns true if the endpoint is initialized
+func (g *Gate) IsOpen() bool {
+	return g.open.Load()
+}
+
+type readinessServer struct {
+	gate *Gate
+}
+
+// NewServer returns a new chain element rejecting requests with Unavailable until the gate is open
+func NewServer(gate *Gate) networkservice.NetworkServiceServer {
+	return &readinessServer{
+		gate: gate,
+	}
+}
+
+func (s *readinessServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if !s.gate.IsOpen() {
+		return nil, status.Error(codes.Unavailable, "endpoint is not initialized yet")
+	}
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *readinessServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
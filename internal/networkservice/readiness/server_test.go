@@ -0,0 +1,56 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package readiness_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/readiness"
+)
+
+func TestReadinessServer(t *testing.T) {
+	gate := new(readiness.Gate)
+	server := readiness.NewServer(gate)
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id"},
+	}
+
+	_, err := server.Request(context.Background(), request)
+	require.Error(t, err)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	_, err = server.Close(context.Background(), request.GetConnection())
+	require.NoError(t, err)
+
+	gate.Open()
+
+	_, err = server.Request(context.Background(), request)
+	require.NoError(t, err)
+
+	gate.Close()
+
+	_, err = server.Request(context.Background(), request)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
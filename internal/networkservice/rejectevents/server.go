@@ -0,0 +1,84 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rejectevents provides chain element emitting events for the rejected requests
+package rejectevents
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/events"
+)
+
+type queuedEvent struct {
+	ctx   context.Context
+	event *events.Event
+}
+
+type rejectEventsServer struct {
+	queue chan queuedEvent
+}
+
+// NewServer returns a new chain element emitting request-rejected events if the next chain elements fail Request.
+// Events are emitted one by one until ctx is done, up to queueSize pending events are kept and the rest are dropped.
+func NewServer(ctx context.Context, emitter events.Emitter, queueSize int) networkservice.NetworkServiceServer {
+	s := &rejectEventsServer{
+		queue: make(chan queuedEvent, queueSize),
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case queued := <-s.queue:
+				emitter.Emit(queued.ctx, queued.event)
+			}
+		}
+	}()
+	return s
+}
+
+func (s *rejectEventsServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		event := &events.Event{
+			Type:    events.RequestRejected,
+			Time:    time.Now(),
+			Message: err.Error(),
+			Attributes: map[string]string{
+				"connection": request.GetConnection().GetId(),
+				"service":    request.GetConnection().GetNetworkService(),
+			},
+		}
+		select {
+		case s.queue <- queuedEvent{ctx: context.WithoutCancel(ctx), event: event}:
+		default:
+			log.FromContext(ctx).Warnf("dropping %s event of %s: event queue is full", event.Type, request.GetConnection().GetId())
+		}
+	}
+	return conn, err
+}
+
+func (s *rejectEventsServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
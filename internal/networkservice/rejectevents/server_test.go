@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rejectevents_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/events"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/rejectevents"
+)
+
+func TestRejectEventsServer(t *testing.T) {
+	received := make(chan *events.Event, 2)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := new(events.Event)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(event))
+		received <- event
+	}))
+	defer webhook.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := chain.NewNetworkServiceServer(
+		rejectevents.NewServer(ctx, events.NewWebhookEmitter("vfio-server", webhook.URL, time.Second), 10),
+		injecterror.NewServer(
+			injecterror.WithError(errors.New("rejected")),
+			injecterror.WithRequestErrorTimes(1),
+		),
+	)
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id", NetworkService: "pingpong"},
+	}
+
+	_, err := server.Request(context.Background(), request)
+	require.NoError(t, err)
+	_, err = server.Request(context.Background(), request)
+	require.Error(t, err)
+
+	event := <-received
+	require.Equal(t, events.RequestRejected, event.Type)
+	require.Equal(t, "vfio-server", event.Endpoint)
+	require.Equal(t, "rejected", event.Message)
+	require.Equal(t, map[string]string{"connection": "id", "service": "pingpong"}, event.Attributes)
+
+	require.Never(t, func() bool { return len(received) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+}
+
+type blockingEmitter struct {
+	started chan struct{}
+	release chan struct{}
+	emitted atomic.Int32
+}
+
+func (e *blockingEmitter) Emit(context.Context, *events.Event) {
+	e.started <- struct{}{}
+	<-e.release
+	e.emitted.Add(1)
+}
+
+func TestRejectEventsServer_QueueFull(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	emitter := &blockingEmitter{started: make(chan struct{}, 10), release: make(chan struct{})}
+	server := chain.NewNetworkServiceServer(
+		rejectevents.NewServer(ctx, emitter, 2),
+		injecterror.NewServer(injecterror.WithError(errors.New("rejected"))),
+	)
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id", NetworkService: "pingpong"},
+	}
+
+	// The worker takes the first event and blocks, 2 more are queued and the rest are dropped without blocking
+	for i := 0; i < 10; i++ {
+		_, err := server.Request(context.Background(), request)
+		require.Error(t, err)
+		if i == 0 {
+			<-emitter.started
+		}
+	}
+
+	close(emitter.release)
+	require.Eventually(t, func() bool { return emitter.emitted.Load() >= 3 }, time.Second, 10*time.Millisecond)
+	require.Never(t, func() bool { return emitter.emitted.Load() > 3 }, 100*time.Millisecond, 10*time.Millisecond)
+}
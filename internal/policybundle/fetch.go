@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policybundle provides fetching of OPA policies distributed as a bundle over HTTP
+package policybundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	regoExt       = ".rego"
+	maxPolicySize = 1 << 20
+)
+
+// Fetch downloads the policy bundle into dir and returns paths to the fetched policies.
+// A bundle URL ending with .rego is treated as a single policy, otherwise as a gzipped tar archive.
+func Fetch(ctx context.Context, bundleURL, dir string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bundleURL, http.NoBody)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid policy bundle URL: %s", bundleURL)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch policy bundle: %s", bundleURL)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch policy bundle: %s: %s", bundleURL, resp.Status)
+	}
+
+	if strings.HasSuffix(req.URL.Path, regoExt) {
+		path := filepath.Join(dir, filepath.Base(req.URL.Path))
+		if err := writePolicy(path, resp.Body); err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+
+	return extract(resp.Body, dir)
+}
+
+func extract(r io.Reader, dir string) ([]string, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "policy bundle is not a gzipped archive")
+	}
+	defer func() { _ = gzipReader.Close() }()
+
+	var paths []string
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read policy bundle")
+		}
+		if header.Typeflag != tar.TypeReg || !strings.HasSuffix(header.Name, regoExt) {
+			continue
+		}
+
+		// Policies are flattened into dir, the index prevents collisions of the same names from different directories
+		path := filepath.Join(dir, fmt.Sprintf("%d-%s", len(paths), filepath.Base(header.Name)))
+		if err := writePolicy(path, tarReader); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	if len(paths) == 0 {
+		return nil, errors.New("policy bundle has no policies")
+	}
+	return paths, nil
+}
+
+func writePolicy(path string, r io.Reader) error {
+	policy, err := io.ReadAll(io.LimitReader(r, maxPolicySize+1))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read policy: %s", path)
+	}
+	if len(policy) > maxPolicySize {
+		return errors.Errorf("policy is too large: %s", path)
+	}
+	if err := os.WriteFile(path, policy, 0o600); err != nil {
+		return errors.Wrapf(err, "failed to write policy: %s", path)
+	}
+	return nil
+}
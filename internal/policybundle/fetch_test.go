@@ -0,0 +1,111 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policybundle_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/policybundle"
+)
+
+const policy = `package nsm
+
+default valid = true
+`
+
+func newBundle(t *testing.T, files map[string]string) []byte {
+	buf := new(bytes.Buffer)
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o600,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return buf.Bytes()
+}
+
+func TestFetch_Bundle(t *testing.T) {
+	bundle := newBundle(t, map[string]string{
+		"common/valid.rego":   policy,
+		"registry/valid.rego": policy,
+		"data.json":           "{}",
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(bundle)
+	}))
+	defer server.Close()
+
+	paths, err := policybundle.Fetch(context.Background(), server.URL+"/bundle.tar.gz", t.TempDir())
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, policy, string(content))
+	}
+}
+
+func TestFetch_Policy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(policy))
+	}))
+	defer server.Close()
+
+	paths, err := policybundle.Fetch(context.Background(), server.URL+"/policies/valid.rego", t.TempDir())
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+}
+
+func TestFetch_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := policybundle.Fetch(context.Background(), server.URL+"/bundle.tar.gz", t.TempDir())
+	require.Error(t, err)
+}
+
+func TestFetch_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := policybundle.Fetch(ctx, server.URL+"/bundle.tar.gz", t.TempDir())
+	require.Error(t, err)
+}
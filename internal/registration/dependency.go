@@ -0,0 +1,47 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registration
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const dependencyRetryInterval = 500 * time.Millisecond
+
+// WaitForDependency probes the TCP address until it accepts a connection, each probe is limited by the timeout.
+// It returns ctx error if ctx is done before the dependency becomes healthy.
+func WaitForDependency(ctx context.Context, address string, timeout time.Duration) error {
+	dialer := &net.Dialer{Timeout: timeout}
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err == nil {
+			_ = conn.Close()
+			return nil
+		}
+		log.FromContext(ctx).Debugf("dependency %s is not healthy: %s", address, err.Error())
+
+		if err := Delay(ctx, dependencyRetryInterval); err != nil {
+			return errors.Wrapf(err, "dependency %s is not healthy", address)
+		}
+	}
+}
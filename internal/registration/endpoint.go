@@ -0,0 +1,120 @@
+// Copyright (c) 2020-2022 Doc.ai and/or its affiliates.
+//
+// Copyright (c) 2023-2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registration
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/buildinfo"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+)
+
+const (
+	// WeightLabel is a label advertising the configured endpoint weight
+	WeightLabel = "weight"
+	// VFIOLabel is a label marking the endpoint as VFIO-capable
+	VFIOLabel = "vfio"
+	// VFIOVersionLabel is a label advertising the VFIO API version supported by the endpoint
+	VFIOVersionLabel = "vfio-version"
+
+	// vfioAPIVersion is VFIO_API_VERSION from linux/vfio.h
+	vfioAPIVersion = "0"
+)
+
+// NewEndpoint returns the network service endpoint to register for the config
+func NewEndpoint(listenOn *url.URL, cfg *config.Config) *registry.NetworkServiceEndpoint {
+	expiry := cfg.MaxTokenLifetime
+	if cfg.RegistrationExpiry > 0 {
+		expiry = cfg.RegistrationExpiry
+	}
+	expireTime := timestamppb.New(time.Now().Add(expiry))
+
+	nse := &registry.NetworkServiceEndpoint{
+		Name:                 cfg.Name,
+		NetworkServiceNames:  make([]string, len(cfg.ServiceNames)),
+		NetworkServiceLabels: make(map[string]*registry.NetworkServiceLabels, len(cfg.ServiceNames)),
+		Url:                  grpcutils.URLToTarget(listenOn),
+		ExpirationTime:       expireTime,
+	}
+
+	for i := range cfg.ServiceNames {
+		service := &cfg.ServiceNames[i]
+
+		nse.NetworkServiceNames[i] = service.Name
+		nse.NetworkServiceLabels[service.Name] = &registry.NetworkServiceLabels{
+			Labels: serviceLabels(cfg, service),
+		}
+	}
+
+	return nse
+}
+
+func serviceLabels(cfg *config.Config, service *config.ServiceConfig) map[string]string {
+	labels := cfg.ServiceLabels(service)
+	if cfg.Weight > 0 {
+		labels[WeightLabel] = strconv.FormatUint(uint64(cfg.Weight), 10)
+	}
+	if cfg.VFIOLabels {
+		labels[VFIOLabel] = "true"
+		labels[VFIOVersionLabel] = vfioAPIVersion
+	}
+	if cfg.BuildInfoLabels {
+		for key, value := range buildinfo.Labels() {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// CheckEndpointSize returns an error if the serialized nse exceeds limit bytes, 0 limit disables the check
+func CheckEndpointSize(nse *registry.NetworkServiceEndpoint, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	if size := proto.Size(nse); size > limit {
+		return errors.Errorf("nse registration is %d bytes, exceeds the limit of %d bytes: reduce labels or services", size, limit)
+	}
+	return nil
+}
+
+// LogEndpoint logs nse as JSON if debug logging is enabled
+func LogEndpoint(ctx context.Context, nse *registry.NetworkServiceEndpoint) {
+	if !logrus.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+	data, err := protojson.Marshal(nse)
+	if err != nil {
+		log.FromContext(ctx).Debugf("failed to marshal nse: %s", err.Error())
+		return
+	}
+	log.FromContext(ctx).Debugf("registering nse: %s", data)
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registration
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// Reconcile periodically finds nse in the registry and registers it again if it is missing or differs from nse.
+// Each interval is extended by a random jitter in [0, jitter). It returns when ctx is done.
+func Reconcile(ctx context.Context, client registry.NetworkServiceEndpointRegistryClient, nse *registry.NetworkServiceEndpoint, interval, jitter time.Duration) {
+	for {
+		if err := Delay(ctx, Jitter(interval, jitter)); err != nil {
+			return
+		}
+
+		if reconciled, err := reconcile(ctx, client, nse); err != nil {
+			log.FromContext(ctx).Warnf("failed to reconcile nse %s: %s", nse.GetName(), err.Error())
+		} else if reconciled != nil {
+			nse = reconciled
+		}
+	}
+}
+
+// Jitter returns interval extended by a random duration in [0, jitter)
+func Jitter(interval, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return interval
+	}
+	// #nosec G404 - jitter doesn't need a cryptographically secure random
+	return interval + time.Duration(rand.Int63n(int64(jitter)))
+}
+
+func reconcile(ctx context.Context, client registry.NetworkServiceEndpointRegistryClient, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	stream, err := client.Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{Name: nse.GetName()},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	found := registry.ReadNetworkServiceEndpointList(stream)
+	for _, actual := range found {
+		if actual.GetName() == nse.GetName() && matches(nse, actual) {
+			return nil, nil
+		}
+	}
+
+	if len(found) == 0 {
+		log.FromContext(ctx).Warnf("nse %s is missing in the registry, registering it again", nse.GetName())
+	} else {
+		log.FromContext(ctx).Warnf("nse %s differs in the registry: %v, registering it again", nse.GetName(), found)
+	}
+	return client.Register(ctx, nse.Clone())
+}
+
+// matches compares the registered attributes of the endpoints ignoring the registration timestamps
+func matches(intended, actual *registry.NetworkServiceEndpoint) bool {
+	if intended.GetUrl() != actual.GetUrl() || len(intended.GetNetworkServiceNames()) != len(actual.GetNetworkServiceNames()) {
+		return false
+	}
+	for i, name := range intended.GetNetworkServiceNames() {
+		if actual.GetNetworkServiceNames()[i] != name {
+			return false
+		}
+		intendedLabels := intended.GetNetworkServiceLabels()[name].GetLabels()
+		actualLabels := actual.GetNetworkServiceLabels()[name].GetLabels()
+		if len(intendedLabels) != len(actualLabels) {
+			return false
+		}
+		for key, value := range intendedLabels {
+			if actualValue, ok := actualLabels[key]; !ok || actualValue != value {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,464 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registration_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/null"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/streamchannel"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/log/logruslogger"
+	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/buildinfo"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/registration"
+)
+
+var listenOn = &url.URL{Scheme: "unix", Path: "/tmp/listen.on"}
+
+func newConfig() *config.Config {
+	return &config.Config{
+		Name:             "vfio-server",
+		MaxTokenLifetime: time.Hour,
+		Labels: map[string]string{
+			"app": "vfio",
+		},
+		ServiceNames: config.ServiceConfigs{
+			{Name: "pingpong"},
+			{Name: "pongping"},
+		},
+	}
+}
+
+func TestNewEndpoint(t *testing.T) {
+	nse := registration.NewEndpoint(listenOn, newConfig())
+
+	require.Equal(t, "vfio-server", nse.GetName())
+	require.Equal(t, "unix:///tmp/listen.on", nse.GetUrl())
+	require.Equal(t, []string{"pingpong", "pongping"}, nse.GetNetworkServiceNames())
+	for _, service := range nse.GetNetworkServiceNames() {
+		require.Equal(t, map[string]string{"app": "vfio"}, nse.GetNetworkServiceLabels()[service].GetLabels())
+	}
+}
+
+func TestNewEndpoint_Weight(t *testing.T) {
+	cfg := newConfig()
+	cfg.Weight = 10
+
+	nse := registration.NewEndpoint(listenOn, cfg)
+
+	for _, service := range nse.GetNetworkServiceNames() {
+		require.Equal(t, "10", nse.GetNetworkServiceLabels()[service].GetLabels()[registration.WeightLabel])
+	}
+	require.NotContains(t, cfg.Labels, registration.WeightLabel)
+}
+
+func TestDelay(t *testing.T) {
+	const delay = 100 * time.Millisecond
+
+	start := time.Now()
+	require.NoError(t, registration.Delay(context.Background(), delay))
+	require.GreaterOrEqual(t, time.Since(start), delay)
+}
+
+func TestDelay_Cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	require.ErrorIs(t, registration.Delay(ctx, time.Minute), context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Minute)
+}
+
+func TestNewEndpoint_ServiceLabels(t *testing.T) {
+	cfg := newConfig()
+	cfg.ServiceNames[0].Labels = map[string]string{
+		"app":  "pingpong",
+		"tier": "gold",
+	}
+
+	nse := registration.NewEndpoint(listenOn, cfg)
+
+	require.Equal(t, map[string]string{
+		"app":  "pingpong",
+		"tier": "gold",
+	}, nse.GetNetworkServiceLabels()["pingpong"].GetLabels())
+	require.Equal(t, map[string]string{
+		"app": "vfio",
+	}, nse.GetNetworkServiceLabels()["pongping"].GetLabels())
+}
+
+func TestNewEndpoint_VFIOLabels(t *testing.T) {
+	cfg := newConfig()
+	cfg.VFIOLabels = true
+
+	nse := registration.NewEndpoint(listenOn, cfg)
+
+	for _, service := range nse.GetNetworkServiceNames() {
+		require.Equal(t, map[string]string{
+			"app":                         "vfio",
+			registration.VFIOLabel:        "true",
+			registration.VFIOVersionLabel: "0",
+		}, nse.GetNetworkServiceLabels()[service].GetLabels())
+	}
+}
+
+func TestNewEndpoint_BuildInfoLabels(t *testing.T) {
+	commit, buildDate := buildinfo.Commit, buildinfo.BuildDate
+	t.Cleanup(func() { buildinfo.Commit, buildinfo.BuildDate = commit, buildDate })
+	buildinfo.Commit, buildinfo.BuildDate = "0123abc", "2026-10-17T00:00:00Z"
+
+	cfg := newConfig()
+
+	nse := registration.NewEndpoint(listenOn, cfg)
+	require.Equal(t, map[string]string{"app": "vfio"}, nse.GetNetworkServiceLabels()["pingpong"].GetLabels())
+
+	cfg.BuildInfoLabels = true
+
+	nse = registration.NewEndpoint(listenOn, cfg)
+	for _, service := range nse.GetNetworkServiceNames() {
+		require.Equal(t, map[string]string{
+			"app":                    "vfio",
+			buildinfo.CommitLabel:    "0123abc",
+			buildinfo.BuildDateLabel: "2026-10-17T00:00:00Z",
+		}, nse.GetNetworkServiceLabels()[service].GetLabels())
+	}
+}
+
+func TestNewEndpoint_Expiry(t *testing.T) {
+	cfg := newConfig()
+
+	before := time.Now()
+	nse := registration.NewEndpoint(listenOn, cfg)
+	require.WithinRange(t, nse.GetExpirationTime().AsTime(), before.Add(time.Hour), time.Now().Add(time.Hour))
+
+	cfg.RegistrationExpiry = time.Minute
+
+	before = time.Now()
+	nse = registration.NewEndpoint(listenOn, cfg)
+	require.WithinRange(t, nse.GetExpirationTime().AsTime(), before.Add(time.Minute), time.Now().Add(time.Minute))
+}
+
+func TestCheckEndpointSize(t *testing.T) {
+	const limit = 4096
+
+	cfg := newConfig()
+	require.NoError(t, registration.CheckEndpointSize(registration.NewEndpoint(listenOn, cfg), limit))
+
+	for i := 0; i < 100; i++ {
+		cfg.Labels[fmt.Sprintf("label-%d", i)] = strings.Repeat("x", 32)
+	}
+	nse := registration.NewEndpoint(listenOn, cfg)
+
+	err := registration.CheckEndpointSize(nse, limit)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "exceeds the limit of 4096 bytes")
+
+	require.NoError(t, registration.CheckEndpointSize(nse, 0))
+}
+
+func TestLogEndpoint(t *testing.T) {
+	hook := test.NewGlobal()
+	defer logrus.SetLevel(logrus.GetLevel())
+
+	ctx := log.WithLog(context.Background(), logruslogger.New(context.Background()))
+	nse := registration.NewEndpoint(listenOn, newConfig())
+
+	logrus.SetLevel(logrus.InfoLevel)
+	registration.LogEndpoint(ctx, nse)
+	require.Empty(t, hook.AllEntries())
+
+	logrus.SetLevel(logrus.DebugLevel)
+	registration.LogEndpoint(ctx, nse)
+	require.Len(t, hook.AllEntries(), 1)
+
+	logged := new(registry.NetworkServiceEndpoint)
+	require.NoError(t, protojson.Unmarshal([]byte(strings.TrimPrefix(hook.LastEntry().Message, "registering nse: ")), logged))
+	require.True(t, proto.Equal(nse, logged))
+}
+
+func TestWaitForDependency(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- registration.WaitForDependency(ctx, address, time.Second) }()
+
+	select {
+	case err = <-errCh:
+		require.FailNow(t, "dependency is healthy before listening", "%v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	listener, err = net.Listen("tcp", address)
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	require.NoError(t, <-errCh)
+}
+
+func TestWaitForDependency_Cancel(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, registration.WaitForDependency(ctx, address, time.Second), context.DeadlineExceeded)
+}
+
+type recordingNSClient struct {
+	registry.NetworkServiceRegistryClient
+
+	mu         sync.Mutex
+	registered []*registry.NetworkService
+	failing    string
+}
+
+func (c *recordingNSClient) Register(_ context.Context, ns *registry.NetworkService, _ ...grpc.CallOption) (*registry.NetworkService, error) {
+	if ns.GetName() == c.failing {
+		return nil, errors.New("failure")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.registered = append(c.registered, ns)
+	return ns, nil
+}
+
+func (c *recordingNSClient) registeredNames() []string {
+	var names []string
+	for _, ns := range c.registered {
+		names = append(names, ns.GetName())
+	}
+	return names
+}
+
+func newNetworkServices(names ...string) []*registry.NetworkService {
+	cfg := &config.Config{Payload: "ETHERNET"}
+	for _, name := range names {
+		cfg.ServiceNames = append(cfg.ServiceNames, config.ServiceConfig{Name: name})
+	}
+	return registration.NewNetworkServices(cfg)
+}
+
+func TestRegisterServices(t *testing.T) {
+	names := []string{"ns-1", "ns-2", "ns-3", "ns-4", "ns-5"}
+
+	for _, concurrency := range []int{0, 1, 2, len(names) + 1} {
+		client := &recordingNSClient{NetworkServiceRegistryClient: null.NewNetworkServiceRegistryClient()}
+
+		require.NoError(t, registration.RegisterServices(context.Background(), client, newNetworkServices(names...), concurrency))
+		require.ElementsMatch(t, names, client.registeredNames())
+	}
+}
+
+func TestRegisterServices_Failure(t *testing.T) {
+	client := &recordingNSClient{
+		NetworkServiceRegistryClient: null.NewNetworkServiceRegistryClient(),
+		failing:                      "ns-2",
+	}
+
+	err := registration.RegisterServices(context.Background(), client, newNetworkServices("ns-1", "ns-2", "ns-3"), 2)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ns(ns-2)")
+	require.ElementsMatch(t, []string{"ns-1", "ns-3"}, client.registeredNames())
+}
+
+func TestRegisterServices_Description(t *testing.T) {
+	cfg := newConfig()
+	cfg.Payload = "ETHERNET"
+	cfg.ServiceNames[0].Description = "ping pong service"
+
+	client := &recordingNSClient{NetworkServiceRegistryClient: null.NewNetworkServiceRegistryClient()}
+	require.NoError(t, registration.RegisterServices(context.Background(), client, registration.NewNetworkServices(cfg), 1))
+
+	require.Len(t, client.registered, 2)
+	require.Equal(t, "pingpong", client.registered[0].GetName())
+	require.Equal(t, "ETHERNET", client.registered[0].GetPayload())
+	require.Len(t, client.registered[0].GetMatches(), 1)
+	require.Equal(t, map[string]string{
+		registration.DescriptionLabel: "ping pong service",
+	}, client.registered[0].GetMatches()[0].GetMetadata().GetLabels())
+	require.Empty(t, client.registered[1].GetMatches())
+
+	// Description doesn't affect the endpoint selection
+	nse := registration.NewEndpoint(listenOn, cfg)
+	require.Len(t, matchutils.MatchEndpoint(map[string]string{"app": "client"}, client.registered[0], nse), 1)
+}
+
+type fakeNSERegistryClient struct {
+	registry.NetworkServiceEndpointRegistryClient
+
+	mu        sync.Mutex
+	entries   map[string]*registry.NetworkServiceEndpoint
+	registers int
+	finds     int
+}
+
+func (c *fakeNSERegistryClient) Register(_ context.Context, nse *registry.NetworkServiceEndpoint, _ ...grpc.CallOption) (*registry.NetworkServiceEndpoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[nse.GetName()] = nse.Clone()
+	c.registers++
+	return nse, nil
+}
+
+func (c *fakeNSERegistryClient) Find(ctx context.Context, query *registry.NetworkServiceEndpointQuery, _ ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.finds++
+	ch := make(chan *registry.NetworkServiceEndpointResponse, 1)
+	if nse, ok := c.entries[query.GetNetworkServiceEndpoint().GetName()]; ok {
+		ch <- &registry.NetworkServiceEndpointResponse{NetworkServiceEndpoint: nse.Clone()}
+	}
+	close(ch)
+	return streamchannel.NewNetworkServiceEndpointFindClient(ctx, ch), nil
+}
+
+func (c *fakeNSERegistryClient) update(f func(entries map[string]*registry.NetworkServiceEndpoint)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f(c.entries)
+}
+
+func (c *fakeNSERegistryClient) registered() (*registry.NetworkServiceEndpoint, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries["vfio-server"], c.registers
+}
+
+func (c *fakeNSERegistryClient) found() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.finds
+}
+
+func TestReconcile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &fakeNSERegistryClient{
+		NetworkServiceEndpointRegistryClient: null.NewNetworkServiceEndpointRegistryClient(),
+		entries:                              make(map[string]*registry.NetworkServiceEndpoint),
+	}
+	nse, err := client.Register(ctx, registration.NewEndpoint(listenOn, newConfig()))
+	require.NoError(t, err)
+
+	go registration.Reconcile(ctx, client, nse, 10*time.Millisecond, 5*time.Millisecond)
+
+	// Entry is in place: reconciliation runs sequentially, so the 4th Find means 3 reconciliations are done
+	require.Eventually(t, func() bool { return client.found() >= 4 }, time.Second, time.Millisecond)
+	_, registers := client.registered()
+	require.Equal(t, 1, registers)
+
+	// Registry drops the entry
+	client.update(func(entries map[string]*registry.NetworkServiceEndpoint) {
+		delete(entries, nse.GetName())
+	})
+	require.Eventually(t, func() bool {
+		registered, _ := client.registered()
+		return registered != nil
+	}, time.Second, 10*time.Millisecond)
+
+	// Registry entry differs
+	client.update(func(entries map[string]*registry.NetworkServiceEndpoint) {
+		entries[nse.GetName()].Url = "tcp://127.0.0.1:5000"
+	})
+	require.Eventually(t, func() bool {
+		registered, _ := client.registered()
+		return registered.GetUrl() == nse.GetUrl()
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestJitter(t *testing.T) {
+	require.Equal(t, time.Minute, registration.Jitter(time.Minute, 0))
+
+	for i := 0; i < 1000; i++ {
+		interval := registration.Jitter(time.Minute, 10*time.Second)
+		require.GreaterOrEqual(t, interval, time.Minute)
+		require.Less(t, interval, time.Minute+10*time.Second)
+	}
+}
+
+func TestInOrder(t *testing.T) {
+	for order, expected := range map[string][]string{
+		config.RegistrationOrderNSFirst:  {"ns", "nse"},
+		config.RegistrationOrderNSEFirst: {"nse", "ns"},
+	} {
+		var calls []string
+		err := registration.InOrder(order,
+			func() error {
+				calls = append(calls, "ns")
+				return nil
+			},
+			func() error {
+				calls = append(calls, "nse")
+				return nil
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, expected, calls, order)
+	}
+}
+
+func TestInOrder_Failure(t *testing.T) {
+	var calls []string
+	err := registration.InOrder(config.RegistrationOrderNSEFirst,
+		func() error {
+			calls = append(calls, "ns")
+			return nil
+		},
+		func() error {
+			calls = append(calls, "nse")
+			return errors.New("registry is down")
+		},
+	)
+	require.EqualError(t, err, "registry is down")
+	require.Equal(t, []string{"nse"}, calls)
+}
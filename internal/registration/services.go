@@ -0,0 +1,92 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+)
+
+// DescriptionLabel is a network service metadata label holding the service description
+const DescriptionLabel = "description"
+
+// NewNetworkServices returns the network services to register for the config. Service description is sent as
+// a metadata-only match, it doesn't affect the endpoint selection.
+func NewNetworkServices(cfg *config.Config) []*registry.NetworkService {
+	services := make([]*registry.NetworkService, len(cfg.ServiceNames))
+	for i := range cfg.ServiceNames {
+		service := &cfg.ServiceNames[i]
+		services[i] = &registry.NetworkService{
+			Name:    service.Name,
+			Payload: cfg.ServicePayload(service),
+		}
+		if service.Description != "" {
+			services[i].Matches = []*registry.Match{{
+				Metadata: &registry.Metadata{
+					Labels: map[string]string{DescriptionLabel: service.Description},
+				},
+			}}
+		}
+	}
+	return services
+}
+
+// RegisterServices registers network services using up to concurrency parallel workers.
+// All services are tried, the returned error describes every failed registration.
+func RegisterServices(ctx context.Context, client registry.NetworkServiceRegistryClient, services []*registry.NetworkService, concurrency int) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]error, len(services))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(services); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				_, results[i] = client.Register(ctx, services[i])
+			}
+		}()
+	}
+	for i := range services {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var failures []string
+	for i, err := range results {
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("ns(%s): %s", services[i].GetName(), err.Error()))
+		}
+	}
+	if len(failures) > 0 {
+		return errors.Errorf("failed to register %d of %d network services: %s", len(failures), len(services), strings.Join(failures, "; "))
+	}
+	return nil
+}
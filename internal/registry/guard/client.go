@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package guard
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+)
+
+type guardNSClient struct {
+	guard *Guard
+}
+
+// NewNetworkServiceRegistryClient returns a new chain element running Register and Unregister under the guard
+func NewNetworkServiceRegistryClient(guard *Guard) registry.NetworkServiceRegistryClient {
+	return &guardNSClient{
+		guard: guard,
+	}
+}
+
+func (c *guardNSClient) Register(ctx context.Context, in *registry.NetworkService, opts ...grpc.CallOption) (resp *registry.NetworkService, err error) {
+	err = c.guard.do(ctx, func() error {
+		resp, err = next.NetworkServiceRegistryClient(ctx).Register(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (c *guardNSClient) Find(ctx context.Context, in *registry.NetworkServiceQuery, opts ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
+	return next.NetworkServiceRegistryClient(ctx).Find(ctx, in, opts...)
+}
+
+func (c *guardNSClient) Unregister(ctx context.Context, in *registry.NetworkService, opts ...grpc.CallOption) (resp *empty.Empty, err error) {
+	err = c.guard.do(ctx, func() error {
+		resp, err = next.NetworkServiceRegistryClient(ctx).Unregister(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+type guardNSEClient struct {
+	guard *Guard
+}
+
+// NewNetworkServiceEndpointRegistryClient returns a new chain element running Register and Unregister under the guard
+func NewNetworkServiceEndpointRegistryClient(guard *Guard) registry.NetworkServiceEndpointRegistryClient {
+	return &guardNSEClient{
+		guard: guard,
+	}
+}
+
+func (c *guardNSEClient) Register(ctx context.Context, in *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (resp *registry.NetworkServiceEndpoint, err error) {
+	err = c.guard.do(ctx, func() error {
+		resp, err = next.NetworkServiceEndpointRegistryClient(ctx).Register(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (c *guardNSEClient) Find(ctx context.Context, in *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	return next.NetworkServiceEndpointRegistryClient(ctx).Find(ctx, in, opts...)
+}
+
+func (c *guardNSEClient) Unregister(ctx context.Context, in *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (resp *empty.Empty, err error) {
+	err = c.guard.do(ctx, func() error {
+		resp, err = next.NetworkServiceEndpointRegistryClient(ctx).Unregister(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package guard_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/null"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/registry/guard"
+)
+
+// concurrencyCounter records the maximum number of concurrent calls
+type concurrencyCounter struct {
+	current atomic.Int32
+	max     atomic.Int32
+}
+
+func (c *concurrencyCounter) call() {
+	current := c.current.Add(1)
+	defer c.current.Add(-1)
+
+	for {
+		if prev := c.max.Load(); current <= prev || c.max.CompareAndSwap(prev, current) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+}
+
+type countingNSClient struct {
+	*concurrencyCounter
+}
+
+func (c countingNSClient) Register(ctx context.Context, in *registry.NetworkService, opts ...grpc.CallOption) (*registry.NetworkService, error) {
+	c.call()
+	return next.NetworkServiceRegistryClient(ctx).Register(ctx, in, opts...)
+}
+
+func (c countingNSClient) Find(ctx context.Context, in *registry.NetworkServiceQuery, opts ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
+	return next.NetworkServiceRegistryClient(ctx).Find(ctx, in, opts...)
+}
+
+func (c countingNSClient) Unregister(ctx context.Context, in *registry.NetworkService, opts ...grpc.CallOption) (*empty.Empty, error) {
+	c.call()
+	return next.NetworkServiceRegistryClient(ctx).Unregister(ctx, in, opts...)
+}
+
+type countingNSEClient struct {
+	*concurrencyCounter
+}
+
+func (c countingNSEClient) Register(ctx context.Context, in *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*registry.NetworkServiceEndpoint, error) {
+	c.call()
+	return next.NetworkServiceEndpointRegistryClient(ctx).Register(ctx, in, opts...)
+}
+
+func (c countingNSEClient) Find(ctx context.Context, in *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	return next.NetworkServiceEndpointRegistryClient(ctx).Find(ctx, in, opts...)
+}
+
+func (c countingNSEClient) Unregister(ctx context.Context, in *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*empty.Empty, error) {
+	c.call()
+	return next.NetworkServiceEndpointRegistryClient(ctx).Unregister(ctx, in, opts...)
+}
+
+func runConcurrently(limit int) int32 {
+	g := guard.New(limit)
+	counter := new(concurrencyCounter)
+	nsClient := chain.NewNetworkServiceRegistryClient(guard.NewNetworkServiceRegistryClient(g), countingNSClient{counter})
+	nseClient := chain.NewNetworkServiceEndpointRegistryClient(guard.NewNetworkServiceEndpointRegistryClient(g), countingNSEClient{counter})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("name-%d", i)
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			_, _ = nsClient.Register(context.Background(), &registry.NetworkService{Name: name})
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = nsClient.Unregister(context.Background(), &registry.NetworkService{Name: name})
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = nseClient.Register(context.Background(), &registry.NetworkServiceEndpoint{Name: name})
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = nseClient.Unregister(context.Background(), &registry.NetworkServiceEndpoint{Name: name})
+		}()
+	}
+	wg.Wait()
+
+	return counter.max.Load()
+}
+
+func TestGuard_Serialized(t *testing.T) {
+	require.Equal(t, int32(1), runConcurrently(1))
+}
+
+func TestGuard_Limit(t *testing.T) {
+	require.LessOrEqual(t, runConcurrently(3), int32(3))
+}
+
+func TestGuard_ContextDone(t *testing.T) {
+	g := guard.New(1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	blocking := chain.NewNetworkServiceEndpointRegistryClient(
+		guard.NewNetworkServiceEndpointRegistryClient(g),
+		&blockingNSEClient{
+			NetworkServiceEndpointRegistryClient: null.NewNetworkServiceEndpointRegistryClient(),
+			started:                              started,
+			release:                              release,
+		},
+	)
+	go func() { _, _ = blocking.Register(context.Background(), new(registry.NetworkServiceEndpoint)) }()
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := blocking.Register(ctx, new(registry.NetworkServiceEndpoint))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+type blockingNSEClient struct {
+	registry.NetworkServiceEndpointRegistryClient
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *blockingNSEClient) Register(ctx context.Context, in *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*registry.NetworkServiceEndpoint, error) {
+	close(c.started)
+	<-c.release
+	return next.NetworkServiceEndpointRegistryClient(ctx).Register(ctx, in, opts...)
+}
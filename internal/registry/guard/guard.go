@@ -0,0 +1,49 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package guard provides registry chain elements limiting the number of concurrent registry mutations
+package guard
+
+import (
+	"context"
+)
+
+// Guard is a semaphore shared by the registry clients
+type Guard struct {
+	slots chan struct{}
+}
+
+// New returns a new Guard allowing up to limit concurrent registry mutations, limit < 1 means 1
+func New(limit int) *Guard {
+	if limit < 1 {
+		limit = 1
+	}
+	return &Guard{
+		slots: make(chan struct{}, limit),
+	}
+}
+
+// do calls f once a slot is acquired, it returns ctx error if ctx is done earlier
+func (g *Guard) do(ctx context.Context, f func() error) error {
+	select {
+	case g.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-g.slots }()
+
+	return f()
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+)
+
+type retryNSClient struct {
+	policy *Policy
+}
+
+// NewNetworkServiceRegistryClient returns a new chain element retrying registry operations according to the policy
+func NewNetworkServiceRegistryClient(policy *Policy) registry.NetworkServiceRegistryClient {
+	return &retryNSClient{
+		policy: policy,
+	}
+}
+
+func (c *retryNSClient) Register(ctx context.Context, in *registry.NetworkService, opts ...grpc.CallOption) (resp *registry.NetworkService, err error) {
+	err = c.policy.do(ctx, "Register", tryTimeout, func(ctx context.Context) error {
+		resp, err = next.NetworkServiceRegistryClient(ctx).Register(ctx, in.Clone(), opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (c *retryNSClient) Find(ctx context.Context, in *registry.NetworkServiceQuery, opts ...grpc.CallOption) (resp registry.NetworkServiceRegistry_FindClient, err error) {
+	err = c.policy.do(ctx, "Find", 0, func(ctx context.Context) error {
+		resp, err = next.NetworkServiceRegistryClient(ctx).Find(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (c *retryNSClient) Unregister(ctx context.Context, in *registry.NetworkService, opts ...grpc.CallOption) (resp *empty.Empty, err error) {
+	err = c.policy.do(ctx, "Unregister", tryTimeout, func(ctx context.Context) error {
+		resp, err = next.NetworkServiceRegistryClient(ctx).Unregister(ctx, in.Clone(), opts...)
+		return err
+	})
+	return resp, err
+}
+
+type retryNSEClient struct {
+	policy *Policy
+}
+
+// NewNetworkServiceEndpointRegistryClient returns a new chain element retrying registry operations according to the
+// policy
+func NewNetworkServiceEndpointRegistryClient(policy *Policy) registry.NetworkServiceEndpointRegistryClient {
+	return &retryNSEClient{
+		policy: policy,
+	}
+}
+
+func (c *retryNSEClient) Register(ctx context.Context, in *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (resp *registry.NetworkServiceEndpoint, err error) {
+	err = c.policy.do(ctx, "Register", tryTimeout, func(ctx context.Context) error {
+		resp, err = next.NetworkServiceEndpointRegistryClient(ctx).Register(ctx, in.Clone(), opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (c *retryNSEClient) Find(ctx context.Context, in *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) (resp registry.NetworkServiceEndpointRegistry_FindClient, err error) {
+	err = c.policy.do(ctx, "Find", 0, func(ctx context.Context) error {
+		resp, err = next.NetworkServiceEndpointRegistryClient(ctx).Find(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (c *retryNSEClient) Unregister(ctx context.Context, in *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (resp *empty.Empty, err error) {
+	err = c.policy.do(ctx, "Unregister", tryTimeout, func(ctx context.Context) error {
+		resp, err = next.NetworkServiceEndpointRegistryClient(ctx).Unregister(ctx, in.Clone(), opts...)
+		return err
+	})
+	return resp, err
+}
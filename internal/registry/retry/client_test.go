@@ -0,0 +1,166 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/chain"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/registry/retry"
+)
+
+var retryable = []codes.Code{codes.Unavailable, codes.DeadlineExceeded}
+
+// failingClient fails the first calls with the errors
+type failingClient struct {
+	errs  []error
+	calls atomic.Int32
+}
+
+func (c *failingClient) call() error {
+	if i := int(c.calls.Add(1)) - 1; i < len(c.errs) {
+		return c.errs[i]
+	}
+	return nil
+}
+
+type failingNSClient struct {
+	*failingClient
+}
+
+func (c failingNSClient) Register(_ context.Context, in *registry.NetworkService, _ ...grpc.CallOption) (*registry.NetworkService, error) {
+	if err := c.call(); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+func (c failingNSClient) Find(context.Context, *registry.NetworkServiceQuery, ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
+	return nil, c.call()
+}
+
+func (c failingNSClient) Unregister(context.Context, *registry.NetworkService, ...grpc.CallOption) (*empty.Empty, error) {
+	return new(empty.Empty), c.call()
+}
+
+type failingNSEClient struct {
+	*failingClient
+}
+
+func (c failingNSEClient) Register(_ context.Context, in *registry.NetworkServiceEndpoint, _ ...grpc.CallOption) (*registry.NetworkServiceEndpoint, error) {
+	if err := c.call(); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+func (c failingNSEClient) Find(context.Context, *registry.NetworkServiceEndpointQuery, ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	return nil, c.call()
+}
+
+func (c failingNSEClient) Unregister(context.Context, *registry.NetworkServiceEndpoint, ...grpc.CallOption) (*empty.Empty, error) {
+	return new(empty.Empty), c.call()
+}
+
+func TestNSEClient_Retryable(t *testing.T) {
+	for name, err := range map[string]error{
+		"unavailable":       status.Error(codes.Unavailable, "registry is down"),
+		"deadline exceeded": status.Error(codes.DeadlineExceeded, "registry is slow"),
+		"no status":         errors.New("failed to dial registry"),
+	} {
+		err := err
+		t.Run(name, func(t *testing.T) {
+			failing := &failingClient{errs: []error{err, err}}
+			client := chain.NewNetworkServiceEndpointRegistryClient(
+				retry.NewNetworkServiceEndpointRegistryClient(retry.NewPolicy(retryable)),
+				failingNSEClient{failing},
+			)
+
+			nse, registerErr := client.Register(context.Background(), &registry.NetworkServiceEndpoint{Name: "vfio-server"})
+			require.NoError(t, registerErr)
+			require.Equal(t, "vfio-server", nse.GetName())
+			require.Equal(t, int32(3), failing.calls.Load())
+		})
+	}
+}
+
+func TestNSEClient_NonRetryable(t *testing.T) {
+	for _, code := range []codes.Code{codes.InvalidArgument, codes.PermissionDenied} {
+		code := code
+		t.Run(code.String(), func(t *testing.T) {
+			failing := &failingClient{errs: []error{status.Error(code, "rejected")}}
+			client := chain.NewNetworkServiceEndpointRegistryClient(
+				retry.NewNetworkServiceEndpointRegistryClient(retry.NewPolicy(retryable)),
+				failingNSEClient{failing},
+			)
+
+			_, err := client.Register(context.Background(), &registry.NetworkServiceEndpoint{Name: "vfio-server"})
+			require.Equal(t, code, status.Code(err))
+			require.Equal(t, int32(1), failing.calls.Load())
+
+			failing.calls.Store(0)
+			_, err = client.Unregister(context.Background(), &registry.NetworkServiceEndpoint{Name: "vfio-server"})
+			require.Equal(t, code, status.Code(err))
+			require.Equal(t, int32(1), failing.calls.Load())
+		})
+	}
+}
+
+func TestNSClient(t *testing.T) {
+	failing := &failingClient{errs: []error{
+		status.Error(codes.Unavailable, "registry is down"),
+		status.Error(codes.InvalidArgument, "invalid payload"),
+	}}
+	client := chain.NewNetworkServiceRegistryClient(
+		retry.NewNetworkServiceRegistryClient(retry.NewPolicy(retryable)),
+		failingNSClient{failing},
+	)
+
+	_, err := client.Register(context.Background(), &registry.NetworkService{Name: "pingpong"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, int32(2), failing.calls.Load())
+
+	_, err = client.Register(context.Background(), &registry.NetworkService{Name: "pingpong"})
+	require.NoError(t, err)
+}
+
+func TestNSEClient_ContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	failing := &failingClient{errs: []error{status.Error(codes.Unavailable, "registry is down")}}
+	client := chain.NewNetworkServiceEndpointRegistryClient(
+		retry.NewNetworkServiceEndpointRegistryClient(retry.NewPolicy(retryable)),
+		failingNSEClient{failing},
+	)
+
+	_, err := client.Register(ctx, &registry.NetworkServiceEndpoint{Name: "vfio-server"})
+	require.Error(t, err)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Equal(t, int32(1), failing.calls.Load())
+}
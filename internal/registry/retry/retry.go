@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retry provides registry chain elements retrying registry operations failed with retryable gRPC codes
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const (
+	retryInterval = 200 * time.Millisecond
+	tryTimeout    = 15 * time.Second
+)
+
+// Policy classifies registry errors into retryable and non-retryable by their gRPC codes
+type Policy struct {
+	retryable map[codes.Code]bool
+}
+
+// NewPolicy returns a new Policy retrying errors with the retryable codes. Errors without a gRPC status are treated
+// as DeadlineExceeded if caused by a deadline and as Unavailable otherwise, like failures to dial the registry.
+func NewPolicy(retryable []codes.Code) *Policy {
+	p := &Policy{
+		retryable: make(map[codes.Code]bool, len(retryable)),
+	}
+	for _, code := range retryable {
+		p.retryable[code] = true
+	}
+	return p
+}
+
+func (p *Policy) isRetryable(err error) bool {
+	code := codes.Unavailable
+	if s, ok := status.FromError(err); ok {
+		code = s.Code()
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		code = codes.DeadlineExceeded
+	}
+	return p.retryable[code]
+}
+
+// do calls f until it succeeds or fails with a non-retryable error, every try is limited by timeout if it is set
+func (p *Policy) do(ctx context.Context, operation string, timeout time.Duration, f func(ctx context.Context) error) error {
+	logger := log.FromContext(ctx).WithField("retry", operation)
+	for {
+		tryCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout > 0 {
+			tryCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		err := f(tryCtx)
+		cancel()
+
+		if err == nil || !p.isRetryable(err) {
+			return err
+		}
+		logger.Warnf("retrying failed attempt: %s", err.Error())
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(err, "%s context is done", operation)
+		case <-time.After(retryInterval):
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package requestid provides gRPC client interceptors propagating a request ID in the outgoing metadata
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// DialOptions returns dial options installing request ID interceptors for the given metadata header
+func DialOptions(header string) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(NewUnaryClientInterceptor(header)),
+		grpc.WithChainStreamInterceptor(NewStreamClientInterceptor(header)),
+	}
+}
+
+// NewUnaryClientInterceptor returns a unary client interceptor setting the request ID header if it is missing
+func NewUnaryClientInterceptor(header string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withRequestID(ctx, header), method, req, reply, cc, opts...)
+	}
+}
+
+// NewStreamClientInterceptor returns a stream client interceptor setting the request ID header if it is missing
+func NewStreamClientInterceptor(header string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withRequestID(ctx, header), desc, cc, method, opts...)
+	}
+}
+
+func withRequestID(ctx context.Context, header string) context.Context {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(header)) > 0 {
+		return ctx
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(header); len(ids) > 0 {
+			return metadata.AppendToOutgoingContext(ctx, header, ids[0])
+		}
+	}
+	return metadata.AppendToOutgoingContext(ctx, header, uuid.New().String())
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requestid_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/requestid"
+)
+
+const header = "x-request-id"
+
+func TestDialOptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ids := make(chan []string, 2)
+	server := grpc.NewServer(grpc.UnaryInterceptor(
+		func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			ids <- md.Get(header)
+			return handler(ctx, req)
+		}))
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+
+	listener := bufconn.Listen(1024 * 1024)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	cc, err := grpc.DialContext(ctx, "bufconn",
+		append(requestid.DialOptions(header),
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)...)
+	require.NoError(t, err)
+	defer func() { _ = cc.Close() }()
+
+	healthClient := grpc_health_v1.NewHealthClient(cc)
+
+	_, err = healthClient.Check(ctx, new(grpc_health_v1.HealthCheckRequest))
+	require.NoError(t, err)
+	generated := <-ids
+	require.Len(t, generated, 1)
+	require.NotEmpty(t, generated[0])
+
+	_, err = healthClient.Check(metadata.AppendToOutgoingContext(ctx, header, "id"), new(grpc_health_v1.HealthCheckRequest))
+	require.NoError(t, err)
+	require.Equal(t, []string{"id"}, <-ids)
+}
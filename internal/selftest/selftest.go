@@ -0,0 +1,117 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package selftest provides a startup self-test requesting connections for the configured network services
+package selftest
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/noop"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+)
+
+// Run requests a noop connection for each service and verifies the returned { MAC, VLAN } mapping. Passthrough and
+// authz-restricted services are skipped, mappings that can't be known in advance are not verified: mappings resolved
+// by the plugin and VLANs selected by the client labels.
+func Run(ctx context.Context, client networkservice.NetworkServiceClient, cfg *config.Config) error {
+	logger := log.FromContext(ctx).WithField("selftest", "Run")
+
+	var failed int
+	for i := range cfg.ServiceNames {
+		service := &cfg.ServiceNames[i]
+		if reason := skipReason(service); reason != "" {
+			logger.Infof("self-test skipped for %s: %s", service.Name, reason)
+			continue
+		}
+		if err := check(ctx, client, cfg, service); err != nil {
+			logger.Errorf("self-test failed for %s: %s", service.Name, err.Error())
+			failed++
+			continue
+		}
+		logger.Infof("self-test passed for %s", service.Name)
+	}
+
+	if failed > 0 {
+		return errors.Errorf("self-test failed for %d of %d services", failed, len(cfg.ServiceNames))
+	}
+	return nil
+}
+
+func skipReason(service *config.ServiceConfig) string {
+	switch {
+	case service.Passthrough:
+		return "passthrough service mapping is not applied"
+	case len(service.AllowedSPIFFEIDs) > 0:
+		return "service is restricted to the allowed SPIFFE IDs"
+	default:
+		return ""
+	}
+}
+
+// selfTestLabels returns the labels passing the domain match of the service
+func selfTestLabels(cfg *config.Config, service *config.ServiceConfig) map[string]string {
+	if cfg.DomainMatchLabel == "" {
+		return nil
+	}
+	return map[string]string{cfg.DomainMatchLabel: cfg.ServiceDomain(service)}
+}
+
+func check(ctx context.Context, client networkservice.NetworkServiceClient, cfg *config.Config, service *config.ServiceConfig) error {
+	conn, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             uuid.New().String(),
+			NetworkService: service.Name,
+			Labels:         selfTestLabels(cfg, service),
+		},
+		MechanismPreferences: []*networkservice.Mechanism{
+			{
+				Cls:  cls.LOCAL,
+				Type: noop.MECHANISM,
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+	defer func() {
+		if _, closeErr := client.Close(ctx, conn); closeErr != nil {
+			log.FromContext(ctx).Warnf("self-test close failed for %s: %s", service.Name, closeErr.Error())
+		}
+	}()
+
+	if cfg.MappingPlugin != "" {
+		return nil
+	}
+
+	// The self-test request sets no destination MAC, so the configured MAC is expected even if the client MAC is
+	// preserved
+	ethernetContext := conn.GetContext().GetEthernetContext()
+	if mac := service.MACAddr.String(); ethernetContext.GetDstMac() != mac {
+		return errors.Errorf("unexpected MAC: %s, expected: %s", ethernetContext.GetDstMac(), mac)
+	}
+	if service.VLANLabel == "" && ethernetContext.GetVlanTag() != service.VLANTag {
+		return errors.Errorf("unexpected VLAN: %d, expected: %d", ethernetContext.GetVlanTag(), service.VLANTag)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selftest_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/noop"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/mapserver"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/selftest"
+)
+
+type fakeResolver struct{}
+
+func (fakeResolver) Resolve(context.Context, string, string) (net.HardwareAddr, int32, error) {
+	return net.HardwareAddr{0x0a, 0x00, 0x00, 0x00, 0x00, 0x01}, 3333, nil
+}
+
+func newClient(cfg *config.Config, opts ...mapserver.Option) networkservice.NetworkServiceClient {
+	return adapters.NewServerToClient(
+		mechanisms.NewServer(map[string]networkservice.NetworkServiceServer{
+			noop.MECHANISM: mapserver.NewServer(cfg, opts...),
+		}),
+	)
+}
+
+func TestRun(t *testing.T) {
+	cfg := &config.Config{
+		ServiceNames: []config.ServiceConfig{
+			{
+				Name:    "pingpong",
+				MACAddr: net.HardwareAddr{0x0a, 0x55, 0x44, 0x33, 0x22, 0x11},
+				VLANTag: 1111,
+			},
+		},
+	}
+
+	require.NoError(t, selftest.Run(context.Background(), newClient(cfg), cfg))
+}
+
+func TestRun_DomainMatch(t *testing.T) {
+	cfg := &config.Config{
+		DomainMatchLabel: "domain",
+		ServiceNames: []config.ServiceConfig{
+			{
+				Name:    "pingpong@worker.domain",
+				MACAddr: net.HardwareAddr{0x0a, 0x55, 0x44, 0x33, 0x22, 0x11},
+				VLANTag: 1111,
+			},
+		},
+	}
+
+	require.NoError(t, selftest.Run(context.Background(), newClient(cfg), cfg))
+}
+
+func TestRun_Mismatch(t *testing.T) {
+	cfg := &config.Config{
+		ServiceNames: []config.ServiceConfig{
+			{
+				Name:    "pingpong",
+				MACAddr: net.HardwareAddr{0x0a, 0x55, 0x44, 0x33, 0x22, 0x11},
+				VLANTag: 1111,
+			},
+		},
+	}
+	expected := &config.Config{
+		ServiceNames: []config.ServiceConfig{
+			{
+				Name:    "pingpong",
+				MACAddr: net.HardwareAddr{0x0a, 0x55, 0x44, 0x33, 0x22, 0x11},
+				VLANTag: 2222,
+			},
+			{
+				Name: "unknown",
+			},
+		},
+	}
+
+	require.Error(t, selftest.Run(context.Background(), newClient(cfg), expected))
+}
+
+func TestRun_DynamicMappings(t *testing.T) {
+	cfg := &config.Config{
+		PreserveClientMAC: true,
+		ServiceNames: []config.ServiceConfig{
+			{
+				Name:        "passthrough",
+				MACAddr:     net.HardwareAddr{0x0a, 0x55, 0x44, 0x33, 0x22, 0x11},
+				VLANTag:     1111,
+				Passthrough: true,
+			},
+			{
+				Name:             "restricted",
+				MACAddr:          net.HardwareAddr{0x0a, 0x55, 0x44, 0x33, 0x22, 0x12},
+				VLANTag:          1112,
+				AllowedSPIFFEIDs: []spiffeid.ID{spiffeid.RequireFromString("spiffe://example.org/client")},
+			},
+			{
+				Name:         "labeled",
+				MACAddr:      net.HardwareAddr{0x0a, 0x55, 0x44, 0x33, 0x22, 0x13},
+				VLANTag:      1113,
+				VLANLabel:    "tier",
+				VLANsByLabel: map[string]int32{"gold": 100},
+			},
+		},
+	}
+
+	require.NoError(t, selftest.Run(context.Background(), newClient(cfg), cfg))
+
+	cfg.MappingPlugin = "unix:///run/mapping.sock"
+	require.NoError(t, selftest.Run(context.Background(), newClient(cfg, mapserver.WithResolver(fakeResolver{}, time.Second)), cfg))
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package socketdir provides creation and cleanup of the temporary directory holding the endpoint server socket
+package socketdir
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const socketName = "listen.on"
+
+// SocketName returns the endpoint server socket file name, randomized name includes PID and a random suffix
+// to avoid collisions of instances sharing a directory
+func SocketName(randomize bool) string {
+	if !randomize {
+		return socketName
+	}
+	return fmt.Sprintf("listen-%d-%s.on", os.Getpid(), uuid.NewString()[:8])
+}
+
+// Create creates a new temporary directory in the first of dirs it succeeds in, empty dir means the system temp
+// directory. Every failed attempt is logged.
+func Create(ctx context.Context, dirs []string, pattern string) (string, error) {
+	var errs []string
+	for _, dir := range dirs {
+		tmpDir, err := os.MkdirTemp(dir, pattern)
+		if err == nil {
+			return tmpDir, nil
+		}
+		log.FromContext(ctx).Warnf("failed to create socket dir in %q: %s", dir, err.Error())
+		errs = append(errs, err.Error())
+	}
+	return "", errors.Errorf("failed to create socket dir in any of %q: %s", dirs, strings.Join(errs, "; "))
+}
+
+// Cleanup removes the unix socket of listenOn and then the whole dir, errors are logged
+func Cleanup(ctx context.Context, dir string, listenOn *url.URL) {
+	if listenOn != nil && listenOn.Scheme == "unix" {
+		if err := os.Remove(listenOn.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.FromContext(ctx).Errorf("failed to remove socket %s: %s", listenOn.Path, err.Error())
+		}
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		log.FromContext(ctx).Errorf("failed to remove dir %s: %s", dir, err.Error())
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package socketdir_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/socketdir"
+)
+
+func TestCleanup(t *testing.T) {
+	dir, err := os.MkdirTemp("", "socketdir")
+	require.NoError(t, err)
+	listenOn := &url.URL{Scheme: "unix", Path: filepath.Join(dir, "listen.on")}
+
+	listener, err := net.Listen("unix", listenOn.Path)
+	require.NoError(t, err)
+	// Keep the socket file after closing the listener like a crashed server does
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, listener.Close())
+	require.FileExists(t, listenOn.Path)
+
+	socketdir.Cleanup(context.Background(), dir, listenOn)
+
+	require.NoFileExists(t, listenOn.Path)
+	require.NoDirExists(t, dir)
+}
+
+func TestCleanup_NoSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "socketdir")
+	require.NoError(t, err)
+
+	socketdir.Cleanup(context.Background(), dir, &url.URL{Scheme: "unix", Path: filepath.Join(dir, "listen.on")})
+
+	require.NoDirExists(t, dir)
+}
+
+func TestSocketName(t *testing.T) {
+	require.Equal(t, "listen.on", socketdir.SocketName(false))
+
+	names := make(map[string]struct{})
+	for i := 0; i < 100; i++ {
+		name := socketdir.SocketName(true)
+		require.Regexp(t, fmt.Sprintf("^listen-%d-[0-9a-f]{8}\\.on$", os.Getpid()), name)
+		names[name] = struct{}{}
+	}
+	require.Len(t, names, 100)
+}
+
+func TestCreate_Fallback(t *testing.T) {
+	parent := t.TempDir()
+	missing := filepath.Join(parent, "missing")
+
+	dir, err := socketdir.Create(context.Background(), []string{missing, parent}, "vfio-server")
+	require.NoError(t, err)
+	require.DirExists(t, dir)
+	require.Equal(t, parent, filepath.Dir(dir))
+}
+
+func TestCreate_AllFail(t *testing.T) {
+	parent := t.TempDir()
+	missing1 := filepath.Join(parent, "missing-1")
+	missing2 := filepath.Join(parent, "missing-2")
+
+	_, err := socketdir.Create(context.Background(), []string{missing1, missing2}, "vfio-server")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), missing1)
+	require.Contains(t, err.Error(), missing2)
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spiffesource provides X509 source recreated on persistent failures
+package spiffesource
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// Source is a closable X509 SVID and bundle source notifying about its updates
+type Source interface {
+	x509svid.Source
+	x509bundle.Source
+	Updated() <-chan struct{}
+	Close() error
+}
+
+// Factory creates a new Source
+type Factory func(ctx context.Context) (Source, error)
+
+// Reloadable is a Source delegating to the recent source created by the factory. TLS configs built on it keep
+// working after the source is recreated.
+type Reloadable struct {
+	factory Factory
+
+	updated chan struct{}
+
+	mu      sync.RWMutex
+	current Source
+	stop    chan struct{}
+}
+
+// New returns a new Reloadable with the source created by the factory
+func New(ctx context.Context, factory Factory) (*Reloadable, error) {
+	source, err := factory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r := &Reloadable{
+		factory: factory,
+		updated: make(chan struct{}, 1),
+		current: source,
+		stop:    make(chan struct{}),
+	}
+	r.forward(source, r.stop)
+	return r, nil
+}
+
+// GetX509SVID returns the X509 SVID of the current source
+func (r *Reloadable) GetX509SVID() (*x509svid.SVID, error) {
+	return r.source().GetX509SVID()
+}
+
+// GetX509BundleForTrustDomain returns the X509 bundle of the current source
+func (r *Reloadable) GetX509BundleForTrustDomain(trustDomain spiffeid.TrustDomain) (*x509bundle.Bundle, error) {
+	return r.source().GetX509BundleForTrustDomain(trustDomain)
+}
+
+// Updated returns a channel notified on updates of the current source and on its recreation
+func (r *Reloadable) Updated() <-chan struct{} {
+	return r.updated
+}
+
+// Close closes the current source
+func (r *Reloadable) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	close(r.stop)
+	r.stop = make(chan struct{})
+	return r.current.Close()
+}
+
+// Watch checks the current source every interval. If it fails to provide a valid X509 SVID, up to attempts new
+// sources are created one per interval, each attempt is limited by the interval. Watch returns an error if none of
+// them is healthy, nil when ctx is done.
+func (r *Reloadable) Watch(ctx context.Context, interval time.Duration, attempts int) error {
+	logger := log.FromContext(ctx).WithField("spiffesource", "Watch")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		err := check(r.source())
+		if err == nil {
+			failures = 0
+			continue
+		}
+		if failures == attempts {
+			return errors.Wrapf(err, "x509 source is not recovered in %d attempts", attempts)
+		}
+		failures++
+
+		logger.Warnf("recreating x509 source, attempt %d of %d: %s", failures, attempts, err.Error())
+		source, err := r.create(ctx, interval)
+		if err == nil {
+			err = check(source)
+			if err != nil {
+				_ = source.Close()
+			}
+		}
+		if err != nil {
+			logger.Warnf("failed to recreate x509 source: %s", err.Error())
+			continue
+		}
+
+		r.swap(source)
+		failures = 0
+		logger.Info("x509 source is recreated")
+	}
+}
+
+// create calls the factory limited by timeout, the factory may block until the first SVID is received
+func (r *Reloadable) create(ctx context.Context, timeout time.Duration) (Source, error) {
+	createCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return r.factory(createCtx)
+}
+
+func (r *Reloadable) source() Source {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.current
+}
+
+func (r *Reloadable) swap(source Source) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	close(r.stop)
+	_ = r.current.Close()
+
+	r.current = source
+	r.stop = make(chan struct{})
+	r.forward(source, r.stop)
+	r.notify()
+}
+
+// forward notifies about updates of the source until stop is closed
+func (r *Reloadable) forward(source Source, stop <-chan struct{}) {
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-source.Updated():
+				r.notify()
+			}
+		}
+	}()
+}
+
+func (r *Reloadable) notify() {
+	select {
+	case r.updated <- struct{}{}:
+	default:
+	}
+}
+
+// check returns an error if the source has no valid X509 SVID
+func check(source Source) error {
+	svid, err := source.GetX509SVID()
+	if err != nil {
+		return err
+	}
+	if len(svid.Certificates) == 0 {
+		return errors.New("x509 svid has no certificates")
+	}
+	if notAfter := svid.Certificates[0].NotAfter; time.Now().After(notAfter) {
+		return errors.Errorf("x509 svid expired at %s", notAfter.Format(time.RFC3339))
+	}
+	return nil
+}
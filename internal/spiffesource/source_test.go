@@ -0,0 +1,183 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spiffesource_test
+
+import (
+	"context"
+	"crypto/x509"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/spiffesource"
+)
+
+type fakeSource struct {
+	mu     sync.Mutex
+	id     string
+	err    error
+	closed bool
+}
+
+func (s *fakeSource) GetX509SVID() (*x509svid.SVID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &x509svid.SVID{
+		ID:           spiffeid.RequireFromString(s.id),
+		Certificates: []*x509.Certificate{{NotAfter: time.Now().Add(time.Hour)}},
+	}, nil
+}
+
+func (s *fakeSource) GetX509BundleForTrustDomain(spiffeid.TrustDomain) (*x509bundle.Bundle, error) {
+	return nil, errors.New("no bundle")
+}
+
+func (s *fakeSource) Updated() <-chan struct{} {
+	return nil
+}
+
+func (s *fakeSource) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSource) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+func (s *fakeSource) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+func TestReloadable_Recover(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	initial := &fakeSource{id: "spiffe://example.org/initial"}
+	var calls atomic.Int32
+	factory := func(context.Context) (spiffesource.Source, error) {
+		switch calls.Add(1) {
+		case 1:
+			return initial, nil
+		case 2, 3:
+			return nil, errors.New("workload API is unavailable")
+		default:
+			return &fakeSource{id: "spiffe://example.org/recovered"}, nil
+		}
+	}
+
+	source, err := spiffesource.New(ctx, factory)
+	require.NoError(t, err)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- source.Watch(ctx, 10*time.Millisecond, 3) }()
+
+	initial.fail(errors.New("source is closed"))
+
+	require.Eventually(t, func() bool {
+		svid, svidErr := source.GetX509SVID()
+		return svidErr == nil && svid.ID.String() == "spiffe://example.org/recovered"
+	}, time.Second, 10*time.Millisecond)
+	require.True(t, initial.isClosed())
+	require.Equal(t, int32(4), calls.Load())
+
+	select {
+	case <-source.Updated():
+	default:
+		require.Fail(t, "recreation of the source is not notified")
+	}
+
+	cancel()
+	require.NoError(t, <-errCh)
+}
+
+func TestReloadable_AttemptsExhausted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	initial := &fakeSource{id: "spiffe://example.org/initial"}
+	var calls atomic.Int32
+	factory := func(context.Context) (spiffesource.Source, error) {
+		if calls.Add(1) == 1 {
+			return initial, nil
+		}
+		return nil, errors.New("workload API is unavailable")
+	}
+
+	source, err := spiffesource.New(ctx, factory)
+	require.NoError(t, err)
+
+	initial.fail(errors.New("source is closed"))
+
+	err = source.Watch(ctx, 10*time.Millisecond, 2)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not recovered in 2 attempts")
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestReloadable_AttemptTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	initial := &fakeSource{id: "spiffe://example.org/initial"}
+	var calls atomic.Int32
+	factory := func(ctx context.Context) (spiffesource.Source, error) {
+		if calls.Add(1) == 1 {
+			return initial, nil
+		}
+		// Workload API source blocks until the first SVID is received
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	source, err := spiffesource.New(ctx, factory)
+	require.NoError(t, err)
+
+	initial.fail(errors.New("source is closed"))
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- source.Watch(ctx, 10*time.Millisecond, 2) }()
+
+	select {
+	case err = <-errCh:
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not recovered in 2 attempts")
+		require.Equal(t, int32(3), calls.Load())
+	case <-time.After(time.Second):
+		require.Fail(t, "blocked attempts are not limited")
+	}
+}
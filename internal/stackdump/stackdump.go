@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stackdump provides dumping of all goroutine stacks to the log
+package stackdump
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"runtime"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const initialBufferSize = 1 << 16
+
+// Dump writes stacks of all goroutines to the log
+func Dump(ctx context.Context) {
+	buf := make([]byte, initialBufferSize)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	log.FromContext(ctx).Infof("goroutine stacks dump:\n%s", buf)
+}
+
+// NotifyOnSignal dumps stacks of all goroutines to the log on each of the signals until ctx is done
+func NotifyOnSignal(ctx context.Context, signals ...os.Signal) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, signals...)
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigCh:
+				Dump(ctx)
+			}
+		}
+	}()
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stackdump_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/log/logruslogger"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/stackdump"
+)
+
+func TestDump(t *testing.T) {
+	hook := test.NewGlobal()
+
+	ctx := log.WithLog(context.Background(), logruslogger.New(context.Background()))
+	stackdump.Dump(ctx)
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	require.Contains(t, entry.Message, "goroutine ")
+	require.Contains(t, entry.Message, "TestDump")
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package startup provides a watchdog bounding the endpoint startup duration
+package startup
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Watchdog calls onTimeout if the startup isn't completed in time, reporting the phase it was stuck in
+type Watchdog struct {
+	timeout time.Duration
+	timer   *time.Timer
+
+	mu    sync.Mutex
+	phase string
+}
+
+// NewWatchdog starts a watchdog calling onTimeout if Stop isn't called within timeout, non-positive timeout disables it
+func NewWatchdog(timeout time.Duration, onTimeout func(err error)) *Watchdog {
+	w := &Watchdog{
+		timeout: timeout,
+	}
+	if timeout > 0 {
+		w.timer = time.AfterFunc(timeout, func() {
+			onTimeout(errors.Errorf("startup is not completed in %v, stuck in phase %s", w.timeout, w.currentPhase()))
+		})
+	}
+	return w
+}
+
+// Phase sets the currently executed startup phase
+func (w *Watchdog) Phase(phase string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.phase = phase
+}
+
+// Stop marks the startup as completed
+func (w *Watchdog) Stop() {
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+}
+
+func (w *Watchdog) currentPhase() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.phase
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package startup_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/startup"
+)
+
+func TestWatchdog_Timeout(t *testing.T) {
+	errCh := make(chan error, 1)
+	watchdog := startup.NewWatchdog(50*time.Millisecond, func(err error) {
+		errCh <- err
+	})
+	defer watchdog.Stop()
+
+	watchdog.Phase("2: retrieve spiffe svid")
+	watchdog.Phase("3: create noop server nse")
+
+	select {
+	case err := <-errCh:
+		require.EqualError(t, err, "startup is not completed in 50ms, stuck in phase 3: create noop server nse")
+	case <-time.After(time.Second):
+		require.FailNow(t, "startup timeout is not triggered")
+	}
+}
+
+func TestWatchdog_Stop(t *testing.T) {
+	errCh := make(chan error, 1)
+	watchdog := startup.NewWatchdog(50*time.Millisecond, func(err error) {
+		errCh <- err
+	})
+	watchdog.Phase("2: retrieve spiffe svid")
+	watchdog.Stop()
+
+	select {
+	case err := <-errCh:
+		require.FailNow(t, "unexpected startup timeout", err.Error())
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWatchdog_Disabled(t *testing.T) {
+	errCh := make(chan error, 1)
+	watchdog := startup.NewWatchdog(0, func(err error) {
+		errCh <- err
+	})
+	defer watchdog.Stop()
+
+	select {
+	case err := <-errCh:
+		require.FailNow(t, "unexpected startup timeout", err.Error())
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package summary provides the endpoint shutdown summary and liveness heartbeat
+package summary
+
+import (
+	"context"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/mapserver"
+)
+
+// Log logs a single structured entry summarizing the endpoint lifetime
+func Log(ctx context.Context, uptime time.Duration, stats *mapserver.Stats) {
+	log.FromContext(ctx).
+		WithField("uptime", uptime.Round(time.Second).String()).
+		WithField("requests", stats.Requests()).
+		WithField("closes", stats.Closes()).
+		Info("shutdown summary")
+}
+
+// Heartbeat logs uptime since start and the number of active connections every interval until ctx is done
+func Heartbeat(ctx context.Context, interval time.Duration, start time.Time, stats *mapserver.Stats) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			log.FromContext(ctx).
+				WithField("uptime", time.Since(start).Round(time.Second).String()).
+				WithField("connections", stats.Active()).
+				Info("heartbeat")
+		}
+	}
+}
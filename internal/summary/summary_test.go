@@ -0,0 +1,101 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package summary_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/log/logruslogger"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/mapserver"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/summary"
+)
+
+func TestLog(t *testing.T) {
+	hook := test.NewGlobal()
+	ctx := log.WithLog(context.Background(), logruslogger.New(context.Background()))
+
+	stats := new(mapserver.Stats)
+	server := mapserver.NewServer(&config.Config{
+		ServiceNames: []config.ServiceConfig{{
+			Name:    "pingpong",
+			MACAddr: net.HardwareAddr{0x0a, 0x55, 0x44, 0x33, 0x22, 0x11},
+		}},
+	}, mapserver.WithStats(stats))
+
+	for _, id := range []string{"id-1", "id-2"} {
+		conn, err := server.Request(ctx, &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{Id: id, NetworkService: "pingpong"},
+		})
+		require.NoError(t, err)
+		if id == "id-1" {
+			_, err = server.Close(ctx, conn)
+			require.NoError(t, err)
+		}
+	}
+
+	summary.Log(ctx, 90*time.Minute+400*time.Millisecond, stats)
+
+	entry := hook.LastEntry()
+	require.Equal(t, "shutdown summary", entry.Message)
+	require.Equal(t, "1h30m0s", entry.Data["uptime"])
+	require.Equal(t, int64(2), entry.Data["requests"])
+	require.Equal(t, int64(1), entry.Data["closes"])
+}
+
+func TestHeartbeat(t *testing.T) {
+	hook := test.NewGlobal()
+	ctx, cancel := context.WithCancel(log.WithLog(context.Background(), logruslogger.New(context.Background())))
+
+	stats := new(mapserver.Stats)
+	server := mapserver.NewServer(&config.Config{
+		ServiceNames: []config.ServiceConfig{{Name: "pingpong"}},
+	}, mapserver.WithStats(stats))
+	for _, id := range []string{"id-1", "id-2"} {
+		_, err := server.Request(ctx, &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{Id: id, NetworkService: "pingpong"},
+		})
+		require.NoError(t, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		summary.Heartbeat(ctx, 10*time.Millisecond, time.Now().Add(-time.Hour), stats)
+	}()
+
+	require.Eventually(t, func() bool {
+		for _, entry := range hook.AllEntries() {
+			if entry.Message == "heartbeat" {
+				return entry.Data["uptime"] == "1h0m0s" && entry.Data["connections"] == int64(2)
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package svidwatcher provides a watcher logging X509 SVID rotations
+package svidwatcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// Source is a x509svid.Source notifying about its updates
+type Source interface {
+	x509svid.Source
+	Updated() <-chan struct{}
+}
+
+// Watch logs X509 SVID changes of the source until ctx is done
+func Watch(ctx context.Context, source Source) {
+	logger := log.FromContext(ctx).WithField("svidwatcher", "Watch")
+
+	var lastExpiry time.Time
+	if svid, err := source.GetX509SVID(); err == nil {
+		lastExpiry = expiry(svid)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-source.Updated():
+		}
+
+		svid, err := source.GetX509SVID()
+		if err != nil {
+			logger.Warnf("failed to get rotated x509 svid: %s", err.Error())
+			continue
+		}
+		if svidExpiry := expiry(svid); !svidExpiry.Equal(lastExpiry) {
+			logger.Infof("x509 svid rotated: %q, expires at %s", svid.ID, svidExpiry.Format(time.RFC3339))
+			lastExpiry = svidExpiry
+		}
+	}
+}
+
+func expiry(svid *x509svid.SVID) time.Time {
+	if len(svid.Certificates) == 0 {
+		return time.Time{}
+	}
+	return svid.Certificates[0].NotAfter
+}
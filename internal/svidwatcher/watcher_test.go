@@ -0,0 +1,105 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svidwatcher_test
+
+import (
+	"context"
+	"crypto/x509"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/log/logruslogger"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/svidwatcher"
+)
+
+type fakeSource struct {
+	mu      sync.Mutex
+	svid    *x509svid.SVID
+	updated chan struct{}
+}
+
+func (s *fakeSource) GetX509SVID() (*x509svid.SVID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.svid, nil
+}
+
+func (s *fakeSource) Updated() <-chan struct{} {
+	return s.updated
+}
+
+func (s *fakeSource) rotate(notAfter time.Time) {
+	s.mu.Lock()
+	s.svid = newSVID(notAfter)
+	s.mu.Unlock()
+	s.updated <- struct{}{}
+}
+
+func newSVID(notAfter time.Time) *x509svid.SVID {
+	return &x509svid.SVID{
+		ID:           spiffeid.RequireFromString("spiffe://example.org/nse"),
+		Certificates: []*x509.Certificate{{NotAfter: notAfter}},
+	}
+}
+
+func TestWatch(t *testing.T) {
+	hook := test.NewGlobal()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = log.WithLog(ctx, logruslogger.New(ctx))
+
+	notAfter := time.Now().Add(time.Hour).Truncate(time.Second)
+	source := &fakeSource{
+		svid:    newSVID(notAfter),
+		updated: make(chan struct{}),
+	}
+
+	done := make(chan struct{})
+	go func() {
+		svidwatcher.Watch(ctx, source)
+		close(done)
+	}()
+
+	// The first update doesn't change SVID, it only waits for the watcher to start
+	source.updated <- struct{}{}
+
+	rotated := notAfter.Add(time.Hour)
+	source.rotate(rotated)
+
+	require.Eventually(t, func() bool {
+		for _, entry := range hook.AllEntries() {
+			if strings.Contains(entry.Message, "x509 svid rotated") &&
+				strings.Contains(entry.Message, rotated.Format(time.RFC3339)) {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package telemetry provides OpenTelemetry resource and tracer provider setup
+package telemetry
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+)
+
+// NewResource returns an OpenTelemetry resource of the endpoint. Attributes from OTEL_RESOURCE_ATTRIBUTES are
+// overridden by the configured ones.
+func NewResource(ctx context.Context, cfg *config.Config) (*resource.Resource, error) {
+	attributes := []attribute.KeyValue{
+		semconv.ServiceNameKey.String(cfg.Name),
+	}
+	for key, value := range map[attribute.Key]string{
+		semconv.ServiceNamespaceKey:      cfg.OpenTelemetryServiceNamespace,
+		semconv.ServiceInstanceIDKey:     cfg.OpenTelemetryServiceInstanceID,
+		semconv.DeploymentEnvironmentKey: cfg.OpenTelemetryDeploymentEnvironment,
+	} {
+		if value != "" {
+			attributes = append(attributes, key.String(value))
+		}
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithAttributes(attributes...),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create OpenTelemetry resource")
+	}
+	return res, nil
+}
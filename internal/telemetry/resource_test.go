@@ -0,0 +1,88 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/telemetry"
+)
+
+func TestNewResource(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.environment=dev,k8s.cluster.name=cluster-1")
+
+	res, err := telemetry.NewResource(context.Background(), &config.Config{
+		Name:                               "vfio-server",
+		OpenTelemetryServiceNamespace:      "nsm-system",
+		OpenTelemetryServiceInstanceID:     "vfio-server-1",
+		OpenTelemetryDeploymentEnvironment: "prod",
+	})
+	require.NoError(t, err)
+
+	expected := map[attribute.Key]string{
+		"service.name":           "vfio-server",
+		"service.namespace":      "nsm-system",
+		"service.instance.id":    "vfio-server-1",
+		"deployment.environment": "prod",
+		"k8s.cluster.name":       "cluster-1",
+	}
+	for key, value := range expected {
+		actual, ok := res.Set().Value(key)
+		require.True(t, ok, key)
+		require.Equal(t, value, actual.AsString(), key)
+	}
+}
+
+func TestNewResource_Empty(t *testing.T) {
+	res, err := telemetry.NewResource(context.Background(), &config.Config{Name: "vfio-server"})
+	require.NoError(t, err)
+
+	for _, key := range []attribute.Key{"service.namespace", "service.instance.id", "deployment.environment"} {
+		require.False(t, res.Set().HasValue(key), key)
+	}
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	ctx := context.Background()
+
+	res, err := telemetry.NewResource(ctx, &config.Config{
+		Name:                          "vfio-server",
+		OpenTelemetryServiceNamespace: "nsm-system",
+	})
+	require.NoError(t, err)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := telemetry.NewTracerProvider(exporter, res)
+
+	_, span := tracerProvider.Tracer("test").Start(ctx, "request")
+	span.End()
+	require.NoError(t, tracerProvider.ForceFlush(ctx))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	namespace, ok := spans[0].Resource.Set().Value("service.namespace")
+	require.True(t, ok)
+	require.Equal(t, "nsm-system", namespace.AsString())
+
+	require.NoError(t, tracerProvider.Shutdown(ctx))
+}
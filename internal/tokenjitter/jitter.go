@@ -0,0 +1,45 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tokenjitter provides token generator with randomly shortened token lifetime
+package tokenjitter
+
+import (
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+)
+
+// GeneratorFunc returns a token generator shortening maxLifetime of every token by a random duration of up to
+// percent of it, so token refreshes of replicas are not synchronized. Tokens are created by the generators returned
+// by newGenerator for the shortened lifetime.
+func GeneratorFunc(newGenerator func(lifetime time.Duration) token.GeneratorFunc, maxLifetime time.Duration, percent int) token.GeneratorFunc {
+	if percent <= 0 {
+		return newGenerator(maxLifetime)
+	}
+	maxJitter := int64(maxLifetime) * int64(percent) / 100
+	return func(authInfo credentials.AuthInfo) (string, time.Time, error) {
+		lifetime := maxLifetime
+		if maxJitter > 0 {
+			// #nosec G404 - jitter doesn't need a cryptographically secure random
+			lifetime -= time.Duration(rand.Int63n(maxJitter + 1))
+		}
+		return newGenerator(lifetime)(authInfo)
+	}
+}
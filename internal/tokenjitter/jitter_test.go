@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenjitter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/tokenjitter"
+)
+
+func newGenerator(lifetime time.Duration) token.GeneratorFunc {
+	return func(credentials.AuthInfo) (string, time.Time, error) {
+		return "token", time.Unix(0, 0).Add(lifetime), nil
+	}
+}
+
+func TestGeneratorFunc(t *testing.T) {
+	generator := tokenjitter.GeneratorFunc(newGenerator, 10*time.Minute, 20)
+
+	lifetimes := make(map[time.Duration]struct{})
+	for i := 0; i < 100; i++ {
+		_, expires, err := generator(nil)
+		require.NoError(t, err)
+
+		lifetime := expires.Sub(time.Unix(0, 0))
+		require.GreaterOrEqual(t, lifetime, 8*time.Minute)
+		require.LessOrEqual(t, lifetime, 10*time.Minute)
+		lifetimes[lifetime] = struct{}{}
+	}
+	require.Greater(t, len(lifetimes), 1)
+}
+
+func TestGeneratorFunc_NoJitter(t *testing.T) {
+	generator := tokenjitter.GeneratorFunc(newGenerator, 10*time.Minute, 0)
+
+	for i := 0; i < 10; i++ {
+		_, expires, err := generator(nil)
+		require.NoError(t, err)
+		require.Equal(t, 10*time.Minute, expires.Sub(time.Unix(0, 0)))
+	}
+}
@@ -1,6 +1,6 @@
 // Copyright (c) 2020-2022 Doc.ai and/or its affiliates.
 //
-// Copyright (c) 2023-2024 Cisco and/or its affiliates.
+// Copyright (c) 2023-2026 Cisco and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -33,19 +33,22 @@ import (
 
 	nested "github.com/antonfisher/nested-logrus-formatter"
 	"github.com/edwarnicke/grpcfd"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
 	"github.com/spiffe/go-spiffe/v2/workloadapi"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"golang.org/x/sys/unix"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
-	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/noop"
-	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/client"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/endpoint"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/authorize"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/null"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/groupipam"
 	registryclient "github.com/networkservicemesh/sdk/pkg/registry/chains/client"
 	registryauthorize "github.com/networkservicemesh/sdk/pkg/registry/common/authorize"
@@ -61,8 +64,36 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/tools/token"
 	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
 
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/buildinfo"
 	"github.com/networkservicemesh/cmd-nse-vfio/internal/config"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/contextdefaults"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/events"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/grpcoptions"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/mappingdump"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/mappingplugin"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/metrics"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/errorbudget"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/expirylimit"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/inflight"
 	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/mapserver"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/mechanismcheck"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/peervalidity"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/readiness"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/networkservice/rejectevents"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/policybundle"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/registration"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/registry/guard"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/registry/retry"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/requestid"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/selftest"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/socketdir"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/spiffesource"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/stackdump"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/startup"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/summary"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/svidwatcher"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/telemetry"
+	"github.com/networkservicemesh/cmd-nse-vfio/internal/tokenjitter"
 )
 
 func main() {
@@ -89,6 +120,7 @@ func main() {
 	if err := debug.Self(); err != nil {
 		log.FromContext(ctx).Infof("%s", err)
 	}
+	log.FromContext(ctx).Infof("commit: %q, build date: %q", buildinfo.Commit, buildinfo.BuildDate)
 
 	// enumerating phases
 	log.FromContext(ctx).Infof("there are 5 phases which will be executed followed by a success message:")
@@ -118,22 +150,79 @@ func main() {
 		syscall.SIGUSR1: logrus.TraceLevel,
 		syscall.SIGUSR2: l,
 	})
+	if cfg.StackDumpSignal != "" {
+		stackDumpSignal := unix.SignalNum(cfg.StackDumpSignal)
+		if stackDumpSignal == 0 {
+			logrus.Fatalf("invalid stack dump signal %s", cfg.StackDumpSignal)
+		}
+		stackdump.NotifyOnSignal(ctx, stackDumpSignal)
+	}
 
 	log.FromContext(ctx).Infof("Config: %#v", cfg)
+	for _, warning := range cfg.Warnings() {
+		log.FromContext(ctx).Warn(warning)
+	}
+	if cfg.MappingDumpPath != "" {
+		if err = mappingdump.Write(cfg.MappingDumpPath, cfg); err != nil {
+			logrus.Fatal(err.Error())
+		}
+	}
 
 	// ********************************************************************************
 	// Configure Open Telemetry
 	// ********************************************************************************
+	telemetryResource, err := telemetry.NewResource(ctx, cfg)
+	if err != nil {
+		log.FromContext(ctx).Fatal(err)
+	}
+	var metricReaders []sdkmetric.Reader
 	if opentelemetry.IsEnabled() {
 		collectorAddress := cfg.OpenTelemetryEndpoint
 		spanExporter := opentelemetry.InitSpanExporter(ctx, collectorAddress)
-		metricExporter := opentelemetry.InitOPTLMetricExporter(ctx, collectorAddress, cfg.MetricsExportInterval)
-		o := opentelemetry.Init(ctx, spanExporter, metricExporter, cfg.Name)
+		if metricReader, readerErr := metrics.NewOTLPReader(ctx, collectorAddress, cfg.MetricsExportInterval); readerErr == nil {
+			metricReaders = append(metricReaders, metricReader)
+		} else {
+			log.FromContext(ctx).Error(readerErr.Error())
+		}
+		if spanExporter != nil {
+			tracerProvider := telemetry.NewTracerProvider(spanExporter, telemetryResource)
+			defer func() {
+				if err = tracerProvider.Shutdown(context.Background()); err != nil {
+					log.FromContext(ctx).Error(err.Error())
+				}
+			}()
+		}
+	}
+
+	// ********************************************************************************
+	// Configure Prometheus
+	// ********************************************************************************
+	if cfg.PrometheusAddress != "" {
+		prometheusReader, prometheusHandler, prometheusErr := metrics.NewPrometheusReader()
+		if prometheusErr != nil {
+			log.FromContext(ctx).Fatal(prometheusErr)
+		}
+		metricReaders = append(metricReaders, prometheusReader)
+		go metrics.ListenAndServe(ctx, cfg.PrometheusAddress, prometheusHandler)
+	}
+
+	if len(metricReaders) > 0 {
+		meterProvider := metrics.NewMeterProvider(telemetryResource, metricReaders...)
 		defer func() {
-			if err = o.Close(); err != nil {
+			if err = meterProvider.Shutdown(context.Background()); err != nil {
 				log.FromContext(ctx).Error(err.Error())
 			}
 		}()
+		if cfg.ServiceInfoMetric {
+			if err = metrics.RegisterServiceInfo(cfg); err != nil {
+				log.FromContext(ctx).Warn(err.Error())
+			}
+		}
+		if cfg.MappingInfoMetric {
+			if err = metrics.RegisterMappingInfo(cfg); err != nil {
+				log.FromContext(ctx).Warn(err.Error())
+			}
+		}
 	}
 
 	// ********************************************************************************
@@ -143,38 +232,93 @@ func main() {
 		go pprofutils.ListenAndServe(ctx, cfg.PprofListenOn)
 	}
 
+	watchdog := startup.NewWatchdog(cfg.StartupTimeout, func(err error) {
+		log.FromContext(ctx).Fatal(err.Error())
+	})
+	defer watchdog.Stop()
+
+	watchdog.Phase("2: retrieve spiffe svid")
 	// ********************************************************************************
 	log.FromContext(ctx).Infof("executing phase 2: retrieving svid, check spire agent logs if this is the last line you see")
 	// ********************************************************************************
-	source, err := workloadapi.NewX509Source(ctx)
+	source, err := spiffesource.New(ctx, func(ctx context.Context) (spiffesource.Source, error) {
+		return workloadapi.NewX509Source(ctx)
+	})
 	if err != nil {
 		logrus.Fatalf("error getting x509 source: %+v", err)
 	}
+	if cfg.SpiffeSourceReloadAttempts > 0 {
+		go func() {
+			if err := source.Watch(ctx, cfg.SpiffeSourceCheckInterval, cfg.SpiffeSourceReloadAttempts); err != nil {
+				log.FromContext(ctx).Fatalf("x509 source failed: %+v", err)
+			}
+		}()
+	}
 	svid, err := source.GetX509SVID()
 	if err != nil {
 		logrus.Fatalf("error getting x509 svid: %+v", err)
 	}
 	log.FromContext(ctx).Infof("SVID: %q", svid.ID)
+	go svidwatcher.Watch(ctx, source)
+
+	tokenGenerator := tokenjitter.GeneratorFunc(func(lifetime time.Duration) token.GeneratorFunc {
+		return spiffejwt.TokenGeneratorFunc(source, lifetime)
+	}, cfg.MaxTokenLifetime, cfg.TokenLifetimeJitter)
 
 	tlsClientConfig := tlsconfig.MTLSClientConfig(source, source, tlsconfig.AuthorizeAny())
 	tlsClientConfig.MinVersion = tls.VersionTLS12
 	tlsServerConfig := tlsconfig.MTLSServerConfig(source, source, tlsconfig.AuthorizeAny())
-	tlsServerConfig.MinVersion = tls.VersionTLS12
+	cfg.MinTLSVersion.Apply(tlsServerConfig)
 
+	watchdog.Phase("3: create noop server nse")
 	// ********************************************************************************
 	log.FromContext(ctx).Infof("executing phase 3: create noop-server network service endpoint")
 	// ********************************************************************************
+	stats := new(mapserver.Stats)
+	mapServerOptions := []mapserver.Option{mapserver.WithStats(stats)}
+	if cfg.MappingPlugin != "" {
+		pluginConn, pluginErr := grpc.DialContext(ctx, cfg.MappingPlugin,
+			grpc.WithTransportCredentials(mappingplugin.TransportCredentials(cfg.MappingPlugin, tlsClientConfig)))
+		if pluginErr != nil {
+			log.FromContext(ctx).Fatalf("error dialing mapping plugin %s: %+v", cfg.MappingPlugin, pluginErr)
+		}
+		defer func() { _ = pluginConn.Close() }()
+		mapServerOptions = append(mapServerOptions, mapserver.WithResolver(mappingplugin.NewClient(pluginConn), cfg.MappingPluginTimeout))
+	}
+	if len(cfg.PayloadContextDefaults) > 0 {
+		payloadDefaults, defaultsErr := contextdefaults.Load(cfg.PayloadContextDefaults)
+		if defaultsErr != nil {
+			log.FromContext(ctx).Fatal(defaultsErr.Error())
+		}
+		mapServerOptions = append(mapServerOptions, mapserver.WithPayloadContextDefaults(payloadDefaults))
+	}
+	readinessGate := new(readiness.Gate)
+	if !cfg.RejectUntilReady {
+		readinessGate.Open()
+	}
+	emitter := events.Discard
+	if cfg.EventsWebhookURL != "" {
+		emitter = events.NewWebhookEmitter(cfg.Name, cfg.EventsWebhookURL, cfg.EventsWebhookTimeout)
+	}
 	responderEndpoint := endpoint.NewServer(ctx,
-		spiffejwt.TokenGeneratorFunc(source, cfg.MaxTokenLifetime),
+		tokenGenerator,
 		endpoint.WithName(cfg.Name),
 		endpoint.WithAuthorizeServer(authorize.NewServer()),
 		endpoint.WithAdditionalFunctionality(
+			rejectevents.NewServer(ctx, emitter, cfg.EventsQueueSize),
+			readiness.NewServer(readinessGate),
+			peerValidityServer(cfg.MinPeerValidity),
+			expiryLimitServer(cfg.RejectExcessiveExpiry, cfg.MaxTokenLifetime),
+			inFlightServer(cfg.MaxInFlightRequests),
+			mechanismcheck.NewServer(noop.MECHANISM),
+			errorBudgetServer(ctx, cancel, cfg.ErrorBudget),
 			groupipam.NewServer(cfg.CidrPrefix),
 			mechanisms.NewServer(map[string]networkservice.NetworkServiceServer{
-				noop.MECHANISM: mapserver.NewServer(cfg),
+				noop.MECHANISM: mapserver.NewServer(cfg, mapServerOptions...),
 			}),
 		))
 
+	watchdog.Phase("4: create grpc and mount nse")
 	// ********************************************************************************
 	log.FromContext(ctx).Infof("executing phase 4: create grpc server and register noop-server")
 	// ********************************************************************************
@@ -188,18 +332,36 @@ func main() {
 			),
 		),
 	)
+	options = append(options, grpcoptions.ServerOptions(cfg)...)
 	server := grpc.NewServer(options...)
 	responderEndpoint.Register(server)
-	tmpDir, err := os.MkdirTemp("", cfg.Name)
+	grpcoptions.RegisterReflection(server, cfg)
+	tmpDir, err := socketdir.Create(ctx, append([]string{""}, cfg.SocketFallbackDirs...), cfg.Name)
 	if err != nil {
 		logrus.Fatalf("error creating tmpDir %+v", err)
 	}
-	defer func(tmpDir string) { _ = os.Remove(tmpDir) }(tmpDir)
-	listenOn := &(url.URL{Scheme: "unix", Path: filepath.Join(tmpDir, "listen.on")})
+	listenOn := &(url.URL{Scheme: "unix", Path: filepath.Join(tmpDir, socketdir.SocketName(cfg.RandomizeSocketName))})
+	defer socketdir.Cleanup(ctx, tmpDir, listenOn)
 	srvErrCh := grpcutils.ListenAndServe(ctx, listenOn, server)
 	exitOnErr(ctx, cancel, srvErrCh)
 	log.FromContext(ctx).Infof("grpc server started")
 
+	if cfg.RegistrationDelay > 0 {
+		log.FromContext(ctx).Infof("delaying registration for %v", cfg.RegistrationDelay)
+		if err = registration.Delay(ctx, cfg.RegistrationDelay); err != nil {
+			log.FromContext(ctx).Warnf("registration is cancelled: %s", err.Error())
+			return
+		}
+	}
+	if cfg.DependencyCheck != "" {
+		log.FromContext(ctx).Infof("waiting for dependency %s", cfg.DependencyCheck)
+		if err = registration.WaitForDependency(ctx, cfg.DependencyCheck, cfg.DependencyTimeout); err != nil {
+			log.FromContext(ctx).Warnf("registration is cancelled: %s", err.Error())
+			return
+		}
+	}
+
+	watchdog.Phase("5: register nse with nsm")
 	// ********************************************************************************
 	log.FromContext(ctx).Infof("executing phase 5: register nse with nsm")
 	// ********************************************************************************
@@ -208,7 +370,7 @@ func main() {
 		grpc.WithBlock(),
 		grpc.WithDefaultCallOptions(
 			grpc.WaitForReady(true),
-			grpc.PerRPCCredentials(token.NewPerRPCCredentials(spiffejwt.TokenGeneratorFunc(source, cfg.MaxTokenLifetime)))),
+			grpc.PerRPCCredentials(token.NewPerRPCCredentials(tokenGenerator))),
 		grpc.WithTransportCredentials(
 			grpcfd.TransportCredentials(
 				credentials.NewTLS(
@@ -219,40 +381,96 @@ func main() {
 		grpcfd.WithChainStreamInterceptor(),
 		grpcfd.WithChainUnaryInterceptor(),
 	)
+	if cfg.RequestIDHeader != "" {
+		clientOptions = append(clientOptions, requestid.DialOptions(cfg.RequestIDHeader)...)
+	}
+
+	registryClientPolicies := cfg.RegistryClientPolicies
+	if cfg.RegistryPolicyBundleURL != "" {
+		policyDir, policyErr := os.MkdirTemp("", cfg.Name+"-policies")
+		if policyErr != nil {
+			logrus.Fatalf("error creating policy dir %+v", policyErr)
+		}
+		defer func(policyDir string) { _ = os.RemoveAll(policyDir) }(policyDir)
+		registryClientPolicies = append(fetchPolicyBundle(ctx, cfg, policyDir), registryClientPolicies...)
+	}
+
+	if cfg.SelfTest {
+		// self-test requests the endpoint itself, so the endpoint should serve them until the self-test is done
+		readinessGate.Open()
+		selfTestClient := client.NewClient(ctx,
+			client.WithName(cfg.Name+"-self-test"),
+			client.WithClientURL(listenOn),
+			client.WithDialOptions(clientOptions...),
+			client.WithoutRefresh(),
+		)
+		if err = selftest.Run(ctx, selfTestClient, cfg); err != nil {
+			if cfg.SelfTestRequired {
+				log.FromContext(ctx).Fatalf("self-test failed: %s", err.Error())
+			}
+			log.FromContext(ctx).Warnf("self-test failed: %s", err.Error())
+		}
+		if cfg.RejectUntilReady {
+			readinessGate.Close()
+		}
+	}
 
-	if cfg.RegisterService {
+	registryGuard := guard.New(cfg.MaxRegistryOperations)
+	retryPolicy := retry.NewPolicy(cfg.RegistryRetryCodes)
+	registerServices := func() error {
+		if !cfg.RegisterService {
+			return nil
+		}
 		nsRegistryClient := registryclient.NewNetworkServiceRegistryClient(ctx,
 			registryclient.WithClientURL(&cfg.ConnectTo),
 			registryclient.WithDialOptions(clientOptions...),
+			registryclient.WithNSRetryClient(retry.NewNetworkServiceRegistryClient(retryPolicy)),
+			registryclient.WithNSAdditionalFunctionality(guard.NewNetworkServiceRegistryClient(registryGuard)),
 			registryclient.WithAuthorizeNSRegistryClient(registryauthorize.NewNetworkServiceRegistryClient(
-				registryauthorize.WithPolicies(cfg.RegistryClientPolicies...))))
-		for i := range cfg.ServiceNames {
-			nsName := cfg.ServiceNames[i].Name
-			if _, err = nsRegistryClient.Register(ctx, &registry.NetworkService{
-				Name:    nsName,
-				Payload: cfg.Payload,
-			}); err != nil {
-				log.FromContext(ctx).Fatalf("failed to register ns(%s) %s", nsName, err.Error())
-			}
-		}
+				registryauthorize.WithPolicies(registryClientPolicies...))))
+		nsList := registration.NewNetworkServices(cfg)
+		return registration.RegisterServices(ctx, nsRegistryClient, nsList, cfg.RegistrationConcurrency)
 	}
 
 	nseRegistryClient := registryclient.NewNetworkServiceEndpointRegistryClient(
 		ctx,
 		registryclient.WithClientURL(&cfg.ConnectTo),
 		registryclient.WithDialOptions(clientOptions...),
+		registryclient.WithNSERetryClient(retry.NewNetworkServiceEndpointRegistryClient(retryPolicy)),
 		registryclient.WithNSEAdditionalFunctionality(
+			guard.NewNetworkServiceEndpointRegistryClient(registryGuard),
 			clientinfo.NewNetworkServiceEndpointRegistryClient(),
 			sendfd.NewNetworkServiceEndpointRegistryClient(),
 		),
 		registryclient.WithAuthorizeNSERegistryClient(registryauthorize.NewNetworkServiceEndpointRegistryClient(
-			registryauthorize.WithPolicies(cfg.RegistryClientPolicies...))),
+			registryauthorize.WithPolicies(registryClientPolicies...))),
 	)
-	nse, err := nseRegistryClient.Register(ctx, registryEndpoint(listenOn, cfg))
-	if err != nil {
-		log.FromContext(ctx).Fatalf("unable to register nse %+v", err)
+	nse := registration.NewEndpoint(listenOn, cfg)
+	registration.LogEndpoint(ctx, nse)
+	if err = registration.CheckEndpointSize(nse, cfg.MaxRegistrationSize); err != nil {
+		log.FromContext(ctx).Fatal(err.Error())
+	}
+	registerEndpoint := func() (registerErr error) {
+		nse, registerErr = nseRegistryClient.Register(ctx, nse)
+		return errors.Wrap(registerErr, "unable to register nse")
+	}
+	if err = registration.InOrder(cfg.RegistrationOrder, registerServices, registerEndpoint); err != nil {
+		log.FromContext(ctx).Fatal(err.Error())
 	}
 	logrus.Infof("nse: %+v", nse)
+	readinessGate.Open()
+	emitter.Emit(ctx, &events.Event{
+		Type:       events.Registered,
+		Attributes: map[string]string{"url": nse.GetUrl()},
+	})
+	if cfg.HeartbeatInterval > 0 {
+		go summary.Heartbeat(ctx, cfg.HeartbeatInterval, starttime, stats)
+	}
+	if cfg.ReconcileInterval > 0 {
+		go registration.Reconcile(ctx, nseRegistryClient, nse, cfg.ReconcileInterval, cfg.ReconcileJitter)
+	}
+
+	watchdog.Stop()
 
 	// ********************************************************************************
 	log.FromContext(ctx).Infof("startup completed in %v", time.Since(starttime))
@@ -260,6 +478,8 @@ func main() {
 
 	// wait for server to exit
 	<-ctx.Done()
+
+	summary.Log(ctx, time.Since(starttime), stats)
 }
 
 func exitOnErr(ctx context.Context, cancel context.CancelFunc, errCh <-chan error) {
@@ -277,25 +497,46 @@ func exitOnErr(ctx context.Context, cancel context.CancelFunc, errCh <-chan erro
 	}(ctx, errCh)
 }
 
-func registryEndpoint(listenOn *url.URL, cfg *config.Config) *registry.NetworkServiceEndpoint {
-	expireTime := timestamppb.New(time.Now().Add(cfg.MaxTokenLifetime))
+func errorBudgetServer(ctx context.Context, cancel context.CancelFunc, threshold int) networkservice.NetworkServiceServer {
+	if threshold <= 0 {
+		return null.NewServer()
+	}
+	return errorbudget.NewServer(threshold, func() {
+		log.FromContext(ctx).Errorf("%d consecutive requests failed, terminating", threshold)
+		cancel()
+	})
+}
 
-	nse := &registry.NetworkServiceEndpoint{
-		Name:                 cfg.Name,
-		NetworkServiceNames:  make([]string, len(cfg.ServiceNames)),
-		NetworkServiceLabels: make(map[string]*registry.NetworkServiceLabels, len(cfg.ServiceNames)),
-		Url:                  grpcutils.URLToTarget(listenOn),
-		ExpirationTime:       expireTime,
+func inFlightServer(limit int) networkservice.NetworkServiceServer {
+	if limit <= 0 {
+		return null.NewServer()
 	}
+	return inflight.NewServer(limit)
+}
 
-	for i := range cfg.ServiceNames {
-		service := &cfg.ServiceNames[i]
+func expiryLimitServer(reject bool, maxLifetime time.Duration) networkservice.NetworkServiceServer {
+	if !reject {
+		return null.NewServer()
+	}
+	return expirylimit.NewServer(maxLifetime)
+}
 
-		nse.NetworkServiceNames[i] = service.Name
-		nse.NetworkServiceLabels[service.Name] = &registry.NetworkServiceLabels{
-			Labels: cfg.Labels,
-		}
+func peerValidityServer(minValidity time.Duration) networkservice.NetworkServiceServer {
+	if minValidity <= 0 {
+		return null.NewServer()
 	}
+	return peervalidity.NewServer(minValidity)
+}
 
-	return nse
+func fetchPolicyBundle(ctx context.Context, cfg *config.Config, dir string) []string {
+	fetchCtx, cancel := context.WithTimeout(ctx, cfg.RegistryPolicyBundleTimeout)
+	defer cancel()
+
+	policies, err := policybundle.Fetch(fetchCtx, cfg.RegistryPolicyBundleURL, dir)
+	if err != nil {
+		log.FromContext(ctx).Warnf("using local registry client policies only: %s", err.Error())
+		return nil
+	}
+	log.FromContext(ctx).Infof("fetched %d registry client policies from %s", len(policies), cfg.RegistryPolicyBundleURL)
+	return policies
 }